@@ -39,6 +39,14 @@ func AddHeader(key string, value string) Option {
 	}
 }
 
+// WebsocketSubprotocol requests the given websocket subprotocol when the request is made over a websocket, eg
+// "graphql-transport-ws". Without it the server falls back to the subscriptions-transport-ws protocol.
+func WebsocketSubprotocol(protocol string) Option {
+	return func(bd *Request) {
+		bd.HTTP.Header.Set("Sec-WebSocket-Protocol", protocol)
+	}
+}
+
 // BasicAuth authenticates the request using http basic auth.
 func BasicAuth(username, password string) Option {
 	return func(bd *Request) {
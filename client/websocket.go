@@ -20,6 +20,16 @@ const (
 	errorMsg          = "error"           // Server -> Client
 )
 
+// graphql-transport-ws protocol messages, see https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const (
+	graphqltransportwsSubprotocol = "graphql-transport-ws"
+
+	subscribeMsg = "subscribe" // Client -> Server
+	nextMsg      = "next"      // Server -> Client
+	pingMsg      = "ping"      // Bidirectional
+	pongMsg      = "pong"      // Bidirectional
+)
+
 type operationMessage struct {
 	Payload json.RawMessage `json:"payload,omitempty"`
 	ID      string          `json:"id,omitempty"`
@@ -46,7 +56,7 @@ func (p *Client) Websocket(query string, options ...Option) *Subscription {
 
 // Grab a single response from a websocket based query
 func (p *Client) WebsocketOnce(query string, resp interface{}, options ...Option) error {
-	sock := p.Websocket(query)
+	sock := p.Websocket(query, options...)
 	defer sock.Close()
 	return sock.Next(&resp)
 }
@@ -102,16 +112,26 @@ func (p *Client) WebsocketWithPayload(query string, initPayload map[string]inter
 		return errorSubscription(fmt.Errorf("expected ack message, got %#v", ack))
 	}
 
-	var ka operationMessage
-	if err = c.ReadJSON(&ka); err != nil {
-		return errorSubscription(fmt.Errorf("ack: %s", err.Error()))
+	graphqltransportws := c.Subprotocol() == graphqltransportwsSubprotocol
+
+	// graphql-transport-ws has no keep-alive message following the ack
+	if !graphqltransportws {
+		var ka operationMessage
+		if err = c.ReadJSON(&ka); err != nil {
+			return errorSubscription(fmt.Errorf("ack: %s", err.Error()))
+		}
+
+		if ka.Type != connectionKaMsg {
+			return errorSubscription(fmt.Errorf("expected ack message, got %#v", ack))
+		}
 	}
 
-	if ka.Type != connectionKaMsg {
-		return errorSubscription(fmt.Errorf("expected ack message, got %#v", ack))
+	start, data := startMsg, dataMsg
+	if graphqltransportws {
+		start, data = subscribeMsg, nextMsg
 	}
 
-	if err = c.WriteJSON(operationMessage{Type: startMsg, ID: "1", Payload: requestBody}); err != nil {
+	if err = c.WriteJSON(operationMessage{Type: start, ID: "1", Payload: requestBody}); err != nil {
 		return errorSubscription(fmt.Errorf("start: %s", err.Error()))
 	}
 
@@ -126,7 +146,18 @@ func (p *Client) WebsocketWithPayload(query string, initPayload map[string]inter
 			if err != nil {
 				return err
 			}
-			if op.Type != dataMsg {
+			// answer server pings until the next result arrives
+			for graphqltransportws && (op.Type == pingMsg || op.Type == pongMsg) {
+				if op.Type == pingMsg {
+					if err = c.WriteJSON(operationMessage{Type: pongMsg}); err != nil {
+						return err
+					}
+				}
+				if err = c.ReadJSON(&op); err != nil {
+					return err
+				}
+			}
+			if op.Type != data {
 				if op.Type == errorMsg {
 					return fmt.Errorf(string(op.Payload))
 				} else {
@@ -104,9 +104,13 @@ func LoadConfig(filename string) (*Config, error) {
 	}
 
 	defaultDirectives := map[string]DirectiveConfig{
-		"skip":       {SkipRuntime: true},
-		"include":    {SkipRuntime: true},
-		"deprecated": {SkipRuntime: true},
+		"skip":         {SkipRuntime: true},
+		"include":      {SkipRuntime: true},
+		"deprecated":   {SkipRuntime: true},
+		"defer":        {SkipRuntime: true},
+		"stream":       {SkipRuntime: true},
+		"live":         {SkipRuntime: true},
+		"cacheControl": {SkipRuntime: true},
 	}
 
 	for key, value := range defaultDirectives {
@@ -268,8 +272,9 @@ func (c *Config) injectTypesFromSchema() error {
 					}
 
 					c.Models[schemaType.Name].Fields[field.Name] = TypeMapField{
-						FieldName: fieldName,
-						Resolver:  forceResolver,
+						FieldName:          fieldName,
+						Resolver:           forceResolver,
+						SubscriptionEvents: c.Models[schemaType.Name].Fields[field.Name].SubscriptionEvents,
 					}
 				}
 			}
@@ -285,9 +290,12 @@ type TypeMapEntry struct {
 }
 
 type TypeMapField struct {
-	Resolver        bool   `yaml:"resolver"`
-	FieldName       string `yaml:"fieldName"`
-	GeneratedMethod string `yaml:"-"`
+	Resolver  bool   `yaml:"resolver"`
+	FieldName string `yaml:"fieldName"`
+	// SubscriptionEvents makes the resolver of a subscription field return a channel of graphql.SubscriptionEvent,
+	// whose events can carry errors.
+	SubscriptionEvents bool   `yaml:"subscriptionEvents"`
+	GeneratedMethod    string `yaml:"-"`
 }
 
 type StringList []string
@@ -28,6 +28,7 @@ type Field struct {
 	Object           *Object          // A link back to the parent object
 	Default          interface{}      // The default value
 	Stream           bool             // does this field return a channel?
+	StreamEvents     bool             // does this field return a channel of graphql.SubscriptionEvent?
 	Directives       []*Directive
 }
 
@@ -62,6 +63,13 @@ func (b *builder) buildField(obj *Object, field *ast.FieldDefinition) (*Field, e
 		f.Args = append(f.Args, newArg)
 	}
 
+	if b.Config.Models[obj.Name].Fields[f.Name].SubscriptionEvents {
+		if !obj.Stream {
+			return nil, errors.Errorf("%s.%s: subscriptionEvents is only supported on subscription fields", obj.Name, field.Name)
+		}
+		f.StreamEvents = true
+	}
+
 	if err = b.bindField(obj, &f); err != nil {
 		f.IsResolver = true
 		log.Println(err.Error())
@@ -482,7 +490,9 @@ func (f *Field) ShortResolverDeclaration() string {
 	}
 
 	result := templates.CurrentImports.LookupType(f.TypeReference.GO)
-	if f.Object.Stream {
+	if f.StreamEvents {
+		result = "<-chan " + templates.CurrentImports.Lookup("github.com/sujamess/fastgql/graphql") + ".SubscriptionEvent"
+	} else if f.Object.Stream {
 		result = "<-chan " + result
 	}
 
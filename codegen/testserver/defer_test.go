@@ -0,0 +1,83 @@
+package testserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql/handler"
+	"github.com/valyala/fasthttp"
+)
+
+func TestDefer(t *testing.T) {
+	resolvers := &Stub{}
+	resolvers.QueryResolver.DeferCase = func(ctx context.Context) (*DeferModel, error) {
+		return &DeferModel{ID: "1", Name: "defer", Values: []string{"a", "b", "c"}}, nil
+	}
+
+	srv := handler.NewDefaultServer(NewExecutableSchema(Config{Resolvers: resolvers}))
+
+	post := func(query string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("/graphql")
+		req.Header.SetMethod("POST")
+		req.Header.SetContentType("application/json")
+		req.SetBody([]byte(`{"query":"` + query + `"}`))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		srv.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	parts := func(payloads ...string) string {
+		var b strings.Builder
+		for _, payload := range payloads {
+			b.WriteString("\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n" + payload)
+		}
+		b.WriteString("\r\n-----\r\n")
+		return b.String()
+	}
+
+	t.Run("without incremental delivery", func(t *testing.T) {
+		resp := post(`{ deferCase { id ... @defer(if: false) { name } } }`)
+		require.Equal(t, "application/json", string(resp.Header.ContentType()))
+		require.Equal(t, `{"data":{"deferCase":{"id":"1","name":"defer"}}}`, string(resp.Body()))
+	})
+
+	t.Run("defer inline fragment", func(t *testing.T) {
+		resp := post(`{ deferCase { id ... @defer(label: \"slow\") { name } } }`)
+		require.Equal(t, `multipart/mixed; boundary="-"`, string(resp.Header.ContentType()))
+		require.Equal(t, parts(
+			`{"data":{"deferCase":{"id":"1"}},"hasNext":true}`,
+			`{"data":{"name":"defer"},"label":"slow","path":["deferCase"],"hasNext":false}`,
+		), string(resp.Body()))
+	})
+
+	t.Run("defer fragment spread", func(t *testing.T) {
+		resp := post(`query { ...F @defer } fragment F on Query { deferCase { name } }`)
+		require.Equal(t, parts(
+			`{"data":{},"hasNext":true}`,
+			`{"data":{"deferCase":{"name":"defer"}},"hasNext":false}`,
+		), string(resp.Body()))
+	})
+
+	t.Run("fields selected without defer are not deferred", func(t *testing.T) {
+		resp := post(`{ deferCase { id name ... @defer { name } } }`)
+		require.Equal(t, `{"data":{"deferCase":{"id":"1","name":"defer"}}}`, string(resp.Body()))
+	})
+
+	t.Run("stream list field", func(t *testing.T) {
+		resp := post(`{ deferCase { values @stream(initialCount: 1, label: \"values\") } }`)
+		require.Equal(t, parts(
+			`{"data":{"deferCase":{"values":["a"]}},"hasNext":true}`,
+			`{"data":"b","label":"values","path":["deferCase","values",1],"hasNext":true}`,
+			`{"data":"c","label":"values","path":["deferCase","values",2],"hasNext":false}`,
+		), string(resp.Body()))
+	})
+}
@@ -135,6 +135,12 @@ type ComplexityRoot struct {
 		Foo func(childComplexity int) int
 	}
 
+	DeferModel struct {
+		ID     func(childComplexity int) int
+		Name   func(childComplexity int) int
+		Values func(childComplexity int) int
+	}
+
 	Dog struct {
 		DogBreed func(childComplexity int) int
 		Species  func(childComplexity int) int
@@ -261,6 +267,7 @@ type ComplexityRoot struct {
 		Autobind                         func(childComplexity int) int
 		Collision                        func(childComplexity int) int
 		DefaultScalar                    func(childComplexity int, arg string) int
+		DeferCase                        func(childComplexity int) int
 		DeprecatedField                  func(childComplexity int) int
 		DirectiveArg                     func(childComplexity int, arg string) int
 		DirectiveDouble                  func(childComplexity int) int
@@ -332,6 +339,7 @@ type ComplexityRoot struct {
 		DirectiveDouble        func(childComplexity int) int
 		DirectiveNullableArg   func(childComplexity int, arg *int, arg2 *int, arg3 *string) int
 		DirectiveUnimplemented func(childComplexity int) int
+		Events                 func(childComplexity int) int
 		InitPayload            func(childComplexity int) int
 		Issue896b              func(childComplexity int) int
 		Updated                func(childComplexity int) int
@@ -432,6 +440,7 @@ type QueryResolver interface {
 	Autobind(ctx context.Context) (*Autobind, error)
 	DeprecatedField(ctx context.Context) (string, error)
 	Overlapping(ctx context.Context) (*OverlappingFields, error)
+	DeferCase(ctx context.Context) (*DeferModel, error)
 	DirectiveArg(ctx context.Context, arg string) (*string, error)
 	DirectiveNullableArg(ctx context.Context, arg *int, arg2 *int, arg3 *string) (*string, error)
 	DirectiveInputNullable(ctx context.Context, arg *InputDirectives) (*string, error)
@@ -481,6 +490,7 @@ type SubscriptionResolver interface {
 	DirectiveDouble(ctx context.Context) (<-chan *string, error)
 	DirectiveUnimplemented(ctx context.Context) (<-chan *string, error)
 	Issue896b(ctx context.Context) (<-chan []*CheckIssue896, error)
+	Events(ctx context.Context) (<-chan graphql.SubscriptionEvent, error)
 }
 type UserResolver interface {
 	Friends(ctx context.Context, obj *User) ([]*User, error)
@@ -503,7 +513,7 @@ func (e *executableSchema) Schema() *ast.Schema {
 }
 
 func (e *executableSchema) Complexity(typeName, field string, childComplexity int, rawArgs map[string]interface{}) (int, bool) {
-	ec := executionContext{nil, e}
+	ec := executionContext{nil, e, 0, 0, nil}
 	_ = ec
 	switch typeName + "." + field {
 
@@ -675,6 +685,27 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.ContentUser.Foo(childComplexity), true
 
+	case "DeferModel.id":
+		if e.complexity.DeferModel.ID == nil {
+			break
+		}
+
+		return e.complexity.DeferModel.ID(childComplexity), true
+
+	case "DeferModel.name":
+		if e.complexity.DeferModel.Name == nil {
+			break
+		}
+
+		return e.complexity.DeferModel.Name(childComplexity), true
+
+	case "DeferModel.values":
+		if e.complexity.DeferModel.Values == nil {
+			break
+		}
+
+		return e.complexity.DeferModel.Values(childComplexity), true
+
 	case "Dog.dogBreed":
 		if e.complexity.Dog.DogBreed == nil {
 			break
@@ -1045,6 +1076,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.DefaultScalar(childComplexity, args["arg"].(string)), true
 
+	case "Query.deferCase":
+		if e.complexity.Query.DeferCase == nil {
+			break
+		}
+
+		return e.complexity.Query.DeferCase(childComplexity), true
+
 	case "Query.deprecatedField":
 		if e.complexity.Query.DeprecatedField == nil {
 			break
@@ -1574,6 +1612,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Subscription.DirectiveUnimplemented(childComplexity), true
 
+	case "Subscription.events":
+		if e.complexity.Subscription.Events == nil {
+			break
+		}
+
+		return e.complexity.Subscription.Events(childComplexity), true
+
 	case "Subscription.initPayload":
 		if e.complexity.Subscription.InitPayload == nil {
 			break
@@ -1733,14 +1778,14 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e}
+	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
 	first := true
 
 	switch rc.Operation.Operation {
 	case ast.Query:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Query(ctx, rc.Operation.SelectionSet)
@@ -1748,13 +1793,14 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 	case ast.Mutation:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Mutation(ctx, rc.Operation.SelectionSet)
@@ -1762,10 +1808,13 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 	case ast.Subscription:
+		// subscriptions deliver every event as a whole, @defer and @stream are ignored
+		ec.deferredResults = nil
 		next := ec._Subscription(ctx, rc.Operation.SelectionSet)
 
 		var buf bytes.Buffer
@@ -1778,9 +1827,14 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			}
 			data.MarshalGQL(&buf)
 
-			return &graphql.Response{
+			response := &graphql.Response{
 				Data: buf.Bytes(),
 			}
+			if event, ok := data.(*graphql.EventMarshaler); ok {
+				response.Errors = event.Errors
+				response.Fatal = event.Fatal
+			}
+			return response
 		}
 
 	default:
@@ -1791,6 +1845,116 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 type executionContext struct {
 	*graphql.OperationContext
 	*executableSchema
+	deferred        int32
+	pendingDeferred int32
+	deferredResults chan graphql.DeferredResult
+}
+
+// deferFields sets the fields of @defer fragments apart, unless the operation delivers its result as a whole.
+func (ec *executionContext) deferFields(fields []graphql.CollectedField) ([]graphql.CollectedField, []graphql.DeferredGroup) {
+	if ec.deferredResults == nil {
+		return fields, nil
+	}
+	return graphql.SplitDeferredFields(fields)
+}
+
+// stream returns the @stream arguments of the list field being resolved, or nil if its items are all part of the
+// current payload.
+func (ec *executionContext) stream(ctx context.Context) *graphql.Stream {
+	if ec.deferredResults == nil {
+		return nil
+	}
+	return graphql.GetStream(ctx)
+}
+
+// processDeferredGroup resolves a deferred group in the background, its result is sent as an incremental payload.
+func (ec *executionContext) processDeferredGroup(ctx context.Context, group graphql.DeferredGroup, f func(ctx context.Context) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, 1)
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		ctx := graphql.WithFreshResponseContext(ctx)
+		ec.sendDeferred(ctx, graphql.DeferredResult{
+			Path:   path,
+			Label:  group.Label,
+			Result: ec.resolveDeferred(ctx, f),
+			Errors: graphql.GetErrors(ctx),
+		})
+	}()
+}
+
+// processStream resolves the list items from index start onwards in the background, each of them is sent as an
+// incremental payload in order.
+func (ec *executionContext) processStream(ctx context.Context, stream *graphql.Stream, start, end int, f func(ctx context.Context, i int) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, int32(end-start))
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		for i := start; i < end; i++ {
+			i := i
+			ctx := graphql.WithFreshResponseContext(ctx)
+			ec.sendDeferred(ctx, graphql.DeferredResult{
+				Path:   append(path[:len(path):len(path)], ast.PathIndex(i)),
+				Label:  stream.Label,
+				Result: ec.resolveDeferred(ctx, func(ctx context.Context) graphql.Marshaler { return f(ctx, i) }),
+				Errors: graphql.GetErrors(ctx),
+			})
+		}
+	}()
+}
+
+func (ec *executionContext) resolveDeferred(ctx context.Context, f func(ctx context.Context) graphql.Marshaler) (res graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			res = graphql.Null
+		}
+	}()
+	return f(ctx)
+}
+
+func (ec *executionContext) sendDeferred(ctx context.Context, result graphql.DeferredResult) {
+	select {
+	case ec.deferredResults <- result:
+	case <-ctx.Done():
+	}
+}
+
+// hasNext reports whether incremental payloads are still to come, it is nil for operations that deferred nothing.
+func (ec *executionContext) hasNext() *bool {
+	if atomic.LoadInt32(&ec.deferred) == 0 {
+		return nil
+	}
+	hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
+	return &hasNext
+}
+
+// nextDeferredResponse waits for the next deferred result and wraps it in an incremental payload.
+func (ec *executionContext) nextDeferredResponse(ctx context.Context) *graphql.Response {
+	if atomic.LoadInt32(&ec.pendingDeferred) == 0 {
+		return nil
+	}
+
+	var result graphql.DeferredResult
+	select {
+	case result = <-ec.deferredResults:
+	case <-ctx.Done():
+		return nil
+	}
+	atomic.AddInt32(&ec.pendingDeferred, -1)
+
+	var buf bytes.Buffer
+	result.Result.MarshalGQL(&buf)
+
+	return &graphql.Response{
+		Errors:  result.Errors,
+		Data:    buf.Bytes(),
+		Label:   result.Label,
+		Path:    result.Path,
+		HasNext: ec.hasNext(),
+	}
 }
 
 func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
@@ -1828,6 +1992,19 @@ type OverlappingFields {
   newFoo: Int!
   new_foo: Int!
 }
+`, BuiltIn: false},
+	{Name: "defer.graphql", Input: `directive @defer(if: Boolean = true, label: String) on FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @stream(if: Boolean = true, label: String, initialCount: Int = 0) on FIELD
+
+extend type Query {
+    deferCase: DeferModel
+}
+
+type DeferModel {
+    id: ID!
+    name: String!
+    values: [String!]!
+}
 `, BuiltIn: false},
 	{Name: "directive.graphql", Input: `directive @length(min: Int!, max: Int, message: String) on ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | FIELD_DEFINITION
 directive @range(min: Int = 0, max: Int) on ARGUMENT_DEFINITION
@@ -2211,6 +2388,10 @@ type Slices {
 }
 
 scalar Bytes
+`, BuiltIn: false},
+	{Name: "subscription_events.graphql", Input: `extend type Subscription {
+    events: String!
+}
 `, BuiltIn: false},
 	{Name: "typefallback.graphql", Input: `extend type Query {
     fallback(arg: FallbackToStringEncoding!): FallbackToStringEncoding!
@@ -4118,6 +4299,102 @@ func (ec *executionContext) _Content_User_foo(ctx context.Context, field graphql
 	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
+func (ec *executionContext) _DeferModel_id(ctx context.Context, field graphql.CollectedField, obj *DeferModel) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:     "DeferModel",
+		Field:      field,
+		Args:       nil,
+		IsMethod:   false,
+		IsResolver: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp := ec._fieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ID, nil
+	})
+
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _DeferModel_name(ctx context.Context, field graphql.CollectedField, obj *DeferModel) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:     "DeferModel",
+		Field:      field,
+		Args:       nil,
+		IsMethod:   false,
+		IsResolver: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp := ec._fieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _DeferModel_values(ctx context.Context, field graphql.CollectedField, obj *DeferModel) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:     "DeferModel",
+		Field:      field,
+		Args:       nil,
+		IsMethod:   false,
+		IsResolver: false,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp := ec._fieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Values, nil
+	})
+
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]string)
+	fc.Result = res
+	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
+}
+
 func (ec *executionContext) _Dog_species(ctx context.Context, field graphql.CollectedField, obj *Dog) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -6237,6 +6514,35 @@ func (ec *executionContext) _Query_overlapping(ctx context.Context, field graphq
 	return ec.marshalOOverlappingFields2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐOverlappingFields(ctx, field.Selections, res)
 }
 
+func (ec *executionContext) _Query_deferCase(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		Args:       nil,
+		IsMethod:   true,
+		IsResolver: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp := ec._fieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().DeferCase(rctx)
+	})
+
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*DeferModel)
+	fc.Result = res
+	return ec.marshalODeferModel2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐDeferModel(ctx, field.Selections, res)
+}
+
 func (ec *executionContext) _Query_directiveArg(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -8304,6 +8610,69 @@ func (ec *executionContext) _Subscription_issue896b(ctx context.Context, field g
 	}
 }
 
+func (ec *executionContext) _Subscription_events(ctx context.Context, field graphql.CollectedField) (ret func() graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = nil
+		}
+	}()
+	fc := &graphql.FieldContext{
+		Object:     "Subscription",
+		Field:      field,
+		Args:       nil,
+		IsMethod:   true,
+		IsResolver: true,
+	}
+
+	ctx = graphql.WithFieldContext(ctx, fc)
+	resTmp := ec._fieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Subscription().Events(rctx)
+	})
+
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return nil
+	}
+	var fatal bool
+	return func() graphql.Marshaler {
+		if fatal {
+			return nil
+		}
+		event, ok := <-resTmp.(<-chan graphql.SubscriptionEvent)
+		if !ok {
+			return nil
+		}
+		// the errors of an event are sent along with it
+		ctx := graphql.WithFreshResponseContext(ctx)
+		res, valid := event.Value.(string)
+		switch {
+		case event.Err != nil:
+			ec.Error(ctx, event.Err)
+			fatal = event.Fatal
+			valid = false
+		case !valid && event.Value != nil:
+			ec.Errorf(ctx, "unexpected type %T from subscription event, should be string", event.Value)
+		}
+		if !valid {
+			if event.Err == nil && event.Value == nil {
+				ec.Errorf(ctx, "must not be null")
+			}
+			return graphql.NewEventMarshaler(ctx, graphql.Null, fatal)
+		}
+		return graphql.NewEventMarshaler(ctx, graphql.WriterFunc(func(w io.Writer) {
+			w.Write([]byte{'{'})
+			graphql.MarshalString(field.Alias).MarshalGQL(w)
+			w.Write([]byte{':'})
+			ec.marshalNString2string(ctx, field.Selections, res).MarshalGQL(w)
+			w.Write([]byte{'}'})
+		}), fatal)
+	}
+}
+
 func (ec *executionContext) _User_id(ctx context.Context, field graphql.CollectedField, obj *User) (ret graphql.Marshaler) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -10489,6 +10858,7 @@ var aImplementors = []string{"A", "TestUnion"}
 
 func (ec *executionContext) _A(ctx context.Context, sel ast.SelectionSet, obj *A) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, aImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10509,6 +10879,12 @@ func (ec *executionContext) _A(ctx context.Context, sel ast.SelectionSet, obj *A
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._A(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10516,6 +10892,7 @@ var aItImplementors = []string{"AIt"}
 
 func (ec *executionContext) _AIt(ctx context.Context, sel ast.SelectionSet, obj *AIt) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, aItImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10536,6 +10913,12 @@ func (ec *executionContext) _AIt(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._AIt(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10543,6 +10926,7 @@ var abItImplementors = []string{"AbIt"}
 
 func (ec *executionContext) _AbIt(ctx context.Context, sel ast.SelectionSet, obj *AbIt) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, abItImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10563,6 +10947,12 @@ func (ec *executionContext) _AbIt(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._AbIt(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10570,6 +10960,7 @@ var autobindImplementors = []string{"Autobind"}
 
 func (ec *executionContext) _Autobind(ctx context.Context, sel ast.SelectionSet, obj *Autobind) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, autobindImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10610,6 +11001,12 @@ func (ec *executionContext) _Autobind(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Autobind(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10617,6 +11014,7 @@ var bImplementors = []string{"B", "TestUnion"}
 
 func (ec *executionContext) _B(ctx context.Context, sel ast.SelectionSet, obj *B) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, bImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10637,6 +11035,12 @@ func (ec *executionContext) _B(ctx context.Context, sel ast.SelectionSet, obj *B
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._B(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10644,6 +11048,7 @@ var backedByInterfaceImplementors = []string{"BackedByInterface"}
 
 func (ec *executionContext) _BackedByInterface(ctx context.Context, sel ast.SelectionSet, obj BackedByInterface) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, backedByInterfaceImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10683,6 +11088,12 @@ func (ec *executionContext) _BackedByInterface(ctx context.Context, sel ast.Sele
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._BackedByInterface(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10690,6 +11101,7 @@ var catImplementors = []string{"Cat", "Animal"}
 
 func (ec *executionContext) _Cat(ctx context.Context, sel ast.SelectionSet, obj *Cat) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, catImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10715,6 +11127,12 @@ func (ec *executionContext) _Cat(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Cat(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10722,6 +11140,7 @@ var checkIssue896Implementors = []string{"CheckIssue896"}
 
 func (ec *executionContext) _CheckIssue896(ctx context.Context, sel ast.SelectionSet, obj *CheckIssue896) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, checkIssue896Implementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10739,6 +11158,12 @@ func (ec *executionContext) _CheckIssue896(ctx context.Context, sel ast.Selectio
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._CheckIssue896(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10746,6 +11171,7 @@ var circleImplementors = []string{"Circle", "Shape", "ShapeUnion"}
 
 func (ec *executionContext) _Circle(ctx context.Context, sel ast.SelectionSet, obj *Circle) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, circleImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10765,6 +11191,12 @@ func (ec *executionContext) _Circle(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Circle(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10772,6 +11204,7 @@ var concreteNodeAImplementors = []string{"ConcreteNodeA", "Node"}
 
 func (ec *executionContext) _ConcreteNodeA(ctx context.Context, sel ast.SelectionSet, obj *ConcreteNodeA) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, concreteNodeAImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10802,6 +11235,12 @@ func (ec *executionContext) _ConcreteNodeA(ctx context.Context, sel ast.Selectio
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ConcreteNodeA(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10809,6 +11248,7 @@ var concreteNodeInterfaceImplementors = []string{"ConcreteNodeInterface", "Node"
 
 func (ec *executionContext) _ConcreteNodeInterface(ctx context.Context, sel ast.SelectionSet, obj ConcreteNodeInterface) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, concreteNodeInterfaceImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10834,6 +11274,12 @@ func (ec *executionContext) _ConcreteNodeInterface(ctx context.Context, sel ast.
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ConcreteNodeInterface(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10841,6 +11287,7 @@ var content_PostImplementors = []string{"Content_Post", "Content_Child"}
 
 func (ec *executionContext) _Content_Post(ctx context.Context, sel ast.SelectionSet, obj *ContentPost) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, content_PostImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10858,6 +11305,12 @@ func (ec *executionContext) _Content_Post(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Content_Post(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10865,6 +11318,7 @@ var content_UserImplementors = []string{"Content_User", "Content_Child"}
 
 func (ec *executionContext) _Content_User(ctx context.Context, sel ast.SelectionSet, obj *ContentUser) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, content_UserImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10882,6 +11336,56 @@ func (ec *executionContext) _Content_User(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Content_User(ctx, group.SelectionSet, obj)
+		})
+	}
+	return out
+}
+
+var deferModelImplementors = []string{"DeferModel"}
+
+func (ec *executionContext) _DeferModel(ctx context.Context, sel ast.SelectionSet, obj *DeferModel) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, deferModelImplementors)
+	fields, deferred := ec.deferFields(fields)
+
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("DeferModel")
+		case "id":
+			out.Values[i] = ec._DeferModel_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "name":
+			out.Values[i] = ec._DeferModel_name(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "values":
+			out.Values[i] = ec._DeferModel_values(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._DeferModel(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10889,6 +11393,7 @@ var dogImplementors = []string{"Dog", "Animal"}
 
 func (ec *executionContext) _Dog(ctx context.Context, sel ast.SelectionSet, obj *Dog) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, dogImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10914,6 +11419,12 @@ func (ec *executionContext) _Dog(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Dog(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10921,6 +11432,7 @@ var embeddedCase1Implementors = []string{"EmbeddedCase1"}
 
 func (ec *executionContext) _EmbeddedCase1(ctx context.Context, sel ast.SelectionSet, obj *EmbeddedCase1) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, embeddedCase1Implementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10941,6 +11453,12 @@ func (ec *executionContext) _EmbeddedCase1(ctx context.Context, sel ast.Selectio
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._EmbeddedCase1(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10948,6 +11466,7 @@ var embeddedCase2Implementors = []string{"EmbeddedCase2"}
 
 func (ec *executionContext) _EmbeddedCase2(ctx context.Context, sel ast.SelectionSet, obj *EmbeddedCase2) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, embeddedCase2Implementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10968,6 +11487,12 @@ func (ec *executionContext) _EmbeddedCase2(ctx context.Context, sel ast.Selectio
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._EmbeddedCase2(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -10975,6 +11500,7 @@ var embeddedCase3Implementors = []string{"EmbeddedCase3"}
 
 func (ec *executionContext) _EmbeddedCase3(ctx context.Context, sel ast.SelectionSet, obj *EmbeddedCase3) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, embeddedCase3Implementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -10995,6 +11521,12 @@ func (ec *executionContext) _EmbeddedCase3(ctx context.Context, sel ast.Selectio
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._EmbeddedCase3(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11002,6 +11534,7 @@ var embeddedDefaultScalarImplementors = []string{"EmbeddedDefaultScalar"}
 
 func (ec *executionContext) _EmbeddedDefaultScalar(ctx context.Context, sel ast.SelectionSet, obj *EmbeddedDefaultScalar) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, embeddedDefaultScalarImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11019,6 +11552,12 @@ func (ec *executionContext) _EmbeddedDefaultScalar(ctx context.Context, sel ast.
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._EmbeddedDefaultScalar(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11026,6 +11565,7 @@ var embeddedPointerImplementors = []string{"EmbeddedPointer"}
 
 func (ec *executionContext) _EmbeddedPointer(ctx context.Context, sel ast.SelectionSet, obj *EmbeddedPointerModel) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, embeddedPointerImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11045,6 +11585,12 @@ func (ec *executionContext) _EmbeddedPointer(ctx context.Context, sel ast.Select
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._EmbeddedPointer(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11052,6 +11598,7 @@ var errorImplementors = []string{"Error"}
 
 func (ec *executionContext) _Error(ctx context.Context, sel ast.SelectionSet, obj *Error) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, errorImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11084,6 +11631,12 @@ func (ec *executionContext) _Error(ctx context.Context, sel ast.SelectionSet, ob
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Error(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11091,6 +11644,7 @@ var errorsImplementors = []string{"Errors"}
 
 func (ec *executionContext) _Errors(ctx context.Context, sel ast.SelectionSet, obj *Errors) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, errorsImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11176,6 +11730,12 @@ func (ec *executionContext) _Errors(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Errors(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11183,6 +11743,7 @@ var forcedResolverImplementors = []string{"ForcedResolver"}
 
 func (ec *executionContext) _ForcedResolver(ctx context.Context, sel ast.SelectionSet, obj *ForcedResolver) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, forcedResolverImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11209,6 +11770,12 @@ func (ec *executionContext) _ForcedResolver(ctx context.Context, sel ast.Selecti
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ForcedResolver(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11216,6 +11783,7 @@ var innerObjectImplementors = []string{"InnerObject"}
 
 func (ec *executionContext) _InnerObject(ctx context.Context, sel ast.SelectionSet, obj *InnerObject) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, innerObjectImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11236,6 +11804,12 @@ func (ec *executionContext) _InnerObject(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._InnerObject(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11243,6 +11817,7 @@ var invalidIdentifierImplementors = []string{"InvalidIdentifier"}
 
 func (ec *executionContext) _InvalidIdentifier(ctx context.Context, sel ast.SelectionSet, obj *invalid_packagename.InvalidIdentifier) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, invalidIdentifierImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11263,6 +11838,12 @@ func (ec *executionContext) _InvalidIdentifier(ctx context.Context, sel ast.Sele
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._InvalidIdentifier(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11270,6 +11851,7 @@ var itImplementors = []string{"It"}
 
 func (ec *executionContext) _It(ctx context.Context, sel ast.SelectionSet, obj *introspection1.It) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, itImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11290,6 +11872,12 @@ func (ec *executionContext) _It(ctx context.Context, sel ast.SelectionSet, obj *
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._It(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11297,6 +11885,7 @@ var loopAImplementors = []string{"LoopA"}
 
 func (ec *executionContext) _LoopA(ctx context.Context, sel ast.SelectionSet, obj *LoopA) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, loopAImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11317,6 +11906,12 @@ func (ec *executionContext) _LoopA(ctx context.Context, sel ast.SelectionSet, ob
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._LoopA(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11324,6 +11919,7 @@ var loopBImplementors = []string{"LoopB"}
 
 func (ec *executionContext) _LoopB(ctx context.Context, sel ast.SelectionSet, obj *LoopB) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, loopBImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11344,6 +11940,12 @@ func (ec *executionContext) _LoopB(ctx context.Context, sel ast.SelectionSet, ob
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._LoopB(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11351,6 +11953,7 @@ var mapImplementors = []string{"Map"}
 
 func (ec *executionContext) _Map(ctx context.Context, sel ast.SelectionSet, obj *Map) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, mapImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11371,6 +11974,12 @@ func (ec *executionContext) _Map(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Map(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11378,6 +11987,7 @@ var mapStringInterfaceTypeImplementors = []string{"MapStringInterfaceType"}
 
 func (ec *executionContext) _MapStringInterfaceType(ctx context.Context, sel ast.SelectionSet, obj map[string]interface{}) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, mapStringInterfaceTypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11397,6 +12007,12 @@ func (ec *executionContext) _MapStringInterfaceType(ctx context.Context, sel ast
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._MapStringInterfaceType(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11404,6 +12020,7 @@ var modelMethodsImplementors = []string{"ModelMethods"}
 
 func (ec *executionContext) _ModelMethods(ctx context.Context, sel ast.SelectionSet, obj *ModelMethods) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, modelMethodsImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11452,6 +12069,12 @@ func (ec *executionContext) _ModelMethods(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ModelMethods(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11459,6 +12082,7 @@ var mutationImplementors = []string{"Mutation"}
 
 func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, mutationImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Mutation",
@@ -11483,6 +12107,12 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Mutation(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -11490,6 +12120,7 @@ var objectDirectivesImplementors = []string{"ObjectDirectives"}
 
 func (ec *executionContext) _ObjectDirectives(ctx context.Context, sel ast.SelectionSet, obj *ObjectDirectives) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, objectDirectivesImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11517,6 +12148,12 @@ func (ec *executionContext) _ObjectDirectives(ctx context.Context, sel ast.Selec
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ObjectDirectives(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11524,6 +12161,7 @@ var objectDirectivesWithCustomGoModelImplementors = []string{"ObjectDirectivesWi
 
 func (ec *executionContext) _ObjectDirectivesWithCustomGoModel(ctx context.Context, sel ast.SelectionSet, obj *ObjectDirectivesWithCustomGoModel) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, objectDirectivesWithCustomGoModelImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11541,6 +12179,12 @@ func (ec *executionContext) _ObjectDirectivesWithCustomGoModel(ctx context.Conte
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ObjectDirectivesWithCustomGoModel(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11548,6 +12192,7 @@ var outerObjectImplementors = []string{"OuterObject"}
 
 func (ec *executionContext) _OuterObject(ctx context.Context, sel ast.SelectionSet, obj *OuterObject) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, outerObjectImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11568,6 +12213,12 @@ func (ec *executionContext) _OuterObject(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._OuterObject(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11575,6 +12226,7 @@ var overlappingFieldsImplementors = []string{"OverlappingFields"}
 
 func (ec *executionContext) _OverlappingFields(ctx context.Context, sel ast.SelectionSet, obj *OverlappingFields) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, overlappingFieldsImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11624,6 +12276,12 @@ func (ec *executionContext) _OverlappingFields(ctx context.Context, sel ast.Sele
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._OverlappingFields(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11631,6 +12289,7 @@ var panicsImplementors = []string{"Panics"}
 
 func (ec *executionContext) _Panics(ctx context.Context, sel ast.SelectionSet, obj *Panics) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, panicsImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11688,6 +12347,12 @@ func (ec *executionContext) _Panics(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Panics(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11695,6 +12360,7 @@ var primitiveImplementors = []string{"Primitive"}
 
 func (ec *executionContext) _Primitive(ctx context.Context, sel ast.SelectionSet, obj *Primitive) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, primitiveImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11729,6 +12395,12 @@ func (ec *executionContext) _Primitive(ctx context.Context, sel ast.SelectionSet
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Primitive(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11736,6 +12408,7 @@ var primitiveStringImplementors = []string{"PrimitiveString"}
 
 func (ec *executionContext) _PrimitiveString(ctx context.Context, sel ast.SelectionSet, obj *PrimitiveString) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, primitiveStringImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -11784,6 +12457,12 @@ func (ec *executionContext) _PrimitiveString(ctx context.Context, sel ast.Select
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._PrimitiveString(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -11791,6 +12470,7 @@ var queryImplementors = []string{"Query"}
 
 func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Query",
@@ -11982,6 +12662,17 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 				res = ec._Query_overlapping(ctx, field)
 				return res
 			})
+		case "deferCase":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_deferCase(ctx, field)
+				return res
+			})
 		case "directiveArg":
 			field := field
 			out.Concurrently(i, func() (res graphql.Marshaler) {
@@ -12473,6 +13164,12 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Query(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -12480,6 +13177,7 @@ var rectangleImplementors = []string{"Rectangle", "Shape", "ShapeUnion"}
 
 func (ec *executionContext) _Rectangle(ctx context.Context, sel ast.SelectionSet, obj *Rectangle) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, rectangleImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12501,6 +13199,12 @@ func (ec *executionContext) _Rectangle(ctx context.Context, sel ast.SelectionSet
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Rectangle(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12508,6 +13212,7 @@ var slicesImplementors = []string{"Slices"}
 
 func (ec *executionContext) _Slices(ctx context.Context, sel ast.SelectionSet, obj *Slices) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, slicesImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12537,6 +13242,12 @@ func (ec *executionContext) _Slices(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Slices(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12567,6 +13278,8 @@ func (ec *executionContext) _Subscription(ctx context.Context, sel ast.Selection
 		return ec._Subscription_directiveUnimplemented(ctx, fields[0])
 	case "issue896b":
 		return ec._Subscription_issue896b(ctx, fields[0])
+	case "events":
+		return ec._Subscription_events(ctx, fields[0])
 	default:
 		panic("unknown field " + strconv.Quote(fields[0].Name))
 	}
@@ -12576,6 +13289,7 @@ var userImplementors = []string{"User"}
 
 func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj *User) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, userImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12617,6 +13331,12 @@ func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._User(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12624,6 +13344,7 @@ var validTypeImplementors = []string{"ValidType"}
 
 func (ec *executionContext) _ValidType(ctx context.Context, sel ast.SelectionSet, obj *ValidType) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, validTypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12659,6 +13380,12 @@ func (ec *executionContext) _ValidType(ctx context.Context, sel ast.SelectionSet
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._ValidType(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12666,6 +13393,7 @@ var wrappedMapImplementors = []string{"WrappedMap"}
 
 func (ec *executionContext) _WrappedMap(ctx context.Context, sel ast.SelectionSet, obj WrappedMap) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, wrappedMapImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12695,6 +13423,12 @@ func (ec *executionContext) _WrappedMap(ctx context.Context, sel ast.SelectionSe
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._WrappedMap(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12702,6 +13436,7 @@ var wrappedSliceImplementors = []string{"WrappedSlice"}
 
 func (ec *executionContext) _WrappedSlice(ctx context.Context, sel ast.SelectionSet, obj WrappedSlice) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, wrappedSliceImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12731,6 +13466,12 @@ func (ec *executionContext) _WrappedSlice(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._WrappedSlice(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12738,6 +13479,7 @@ var wrappedStructImplementors = []string{"WrappedStruct"}
 
 func (ec *executionContext) _WrappedStruct(ctx context.Context, sel ast.SelectionSet, obj *WrappedStruct) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, wrappedStructImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12760,6 +13502,12 @@ func (ec *executionContext) _WrappedStruct(ctx context.Context, sel ast.Selectio
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._WrappedStruct(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12767,6 +13515,7 @@ var xXItImplementors = []string{"XXIt"}
 
 func (ec *executionContext) _XXIt(ctx context.Context, sel ast.SelectionSet, obj *XXIt) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, xXItImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12787,6 +13536,12 @@ func (ec *executionContext) _XXIt(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._XXIt(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12794,6 +13549,7 @@ var xxItImplementors = []string{"XxIt"}
 
 func (ec *executionContext) _XxIt(ctx context.Context, sel ast.SelectionSet, obj *XxIt) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, xxItImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12814,6 +13570,12 @@ func (ec *executionContext) _XxIt(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._XxIt(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12821,6 +13583,7 @@ var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __DirectiveImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12853,6 +13616,12 @@ func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Directive(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12860,6 +13629,7 @@ var __EnumValueImplementors = []string{"__EnumValue"}
 
 func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.EnumValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __EnumValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12889,6 +13659,12 @@ func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___EnumValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12896,6 +13672,7 @@ var __FieldImplementors = []string{"__Field"}
 
 func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet, obj *introspection.Field) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __FieldImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12935,6 +13712,12 @@ func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Field(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12942,6 +13725,7 @@ var __InputValueImplementors = []string{"__InputValue"}
 
 func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.InputValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __InputValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -12971,6 +13755,12 @@ func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___InputValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -12978,6 +13768,7 @@ var __SchemaImplementors = []string{"__Schema"}
 
 func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet, obj *introspection.Schema) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __SchemaImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -13012,6 +13803,12 @@ func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Schema(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -13019,6 +13816,7 @@ var __TypeImplementors = []string{"__Type"}
 
 func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -13055,6 +13853,12 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Type(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -13062,6 +13866,7 @@ var asdfItImplementors = []string{"asdfIt"}
 
 func (ec *executionContext) _asdfIt(ctx context.Context, sel ast.SelectionSet, obj *AsdfIt) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, asdfItImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -13082,6 +13887,12 @@ func (ec *executionContext) _asdfIt(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._asdfIt(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -13089,6 +13900,7 @@ var iItImplementors = []string{"iIt"}
 
 func (ec *executionContext) _iIt(ctx context.Context, sel ast.SelectionSet, obj *IIt) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, iItImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -13109,6 +13921,12 @@ func (ec *executionContext) _iIt(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._iIt(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -13384,11 +14202,25 @@ func (ec *executionContext) unmarshalNMarshalPanic2ᚕgithubᚗcomᚋarsmnᚋfas
 }
 
 func (ec *executionContext) marshalNMarshalPanic2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐMarshalPanicᚄ(ctx context.Context, sel ast.SelectionSet, v []MarshalPanic) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalNMarshalPanic2githubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐMarshalPanic(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNMarshalPanic2githubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐMarshalPanic(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13412,13 +14244,18 @@ func (ec *executionContext) marshalNPrimitive2githubᚗcomᚋarsmnᚋfastgqlᚋc
 }
 
 func (ec *executionContext) marshalNPrimitive2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐPrimitiveᚄ(ctx context.Context, sel ast.SelectionSet, v []Primitive) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13445,6 +14282,15 @@ func (ec *executionContext) marshalNPrimitive2ᚕgithubᚗcomᚋarsmnᚋfastgql
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNPrimitive2githubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐPrimitive(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13453,13 +14299,18 @@ func (ec *executionContext) marshalNPrimitiveString2githubᚗcomᚋarsmnᚋfastg
 }
 
 func (ec *executionContext) marshalNPrimitiveString2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐPrimitiveStringᚄ(ctx context.Context, sel ast.SelectionSet, v []PrimitiveString) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13486,6 +14337,15 @@ func (ec *executionContext) marshalNPrimitiveString2ᚕgithubᚗcomᚋarsmnᚋfa
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNPrimitiveString2githubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐPrimitiveString(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13546,11 +14406,25 @@ func (ec *executionContext) unmarshalNString2ᚕstringᚄ(ctx context.Context, v
 }
 
 func (ec *executionContext) marshalNString2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNString2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13576,11 +14450,25 @@ func (ec *executionContext) unmarshalNString2ᚕᚖstring(ctx context.Context, v
 }
 
 func (ec *executionContext) marshalNString2ᚕᚖstring(ctx context.Context, sel ast.SelectionSet, v []*string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalOString2ᚖstring(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOString2ᚖstring(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13640,13 +14528,18 @@ func (ec *executionContext) marshalNUser2githubᚗcomᚋarsmnᚋfastgqlᚋcodege
 }
 
 func (ec *executionContext) marshalNUser2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐUserᚄ(ctx context.Context, sel ast.SelectionSet, v []*User) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13673,6 +14566,15 @@ func (ec *executionContext) marshalNUser2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNUser2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐUser(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13741,13 +14643,18 @@ func (ec *executionContext) marshalN__Directive2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirectiveᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Directive) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13774,6 +14681,15 @@ func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Directive2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirective(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13814,13 +14730,18 @@ func (ec *executionContext) unmarshalN__DirectiveLocation2ᚕstringᚄ(ctx conte
 }
 
 func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13847,6 +14768,15 @@ func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__DirectiveLocation2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13863,13 +14793,18 @@ func (ec *executionContext) marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.InputValue) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13896,6 +14831,15 @@ func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -13904,13 +14848,18 @@ func (ec *executionContext) marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgrap
 }
 
 func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Type) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -13937,6 +14886,15 @@ func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14021,13 +14979,18 @@ func (ec *executionContext) marshalOCheckIssue8962ᚕᚖgithubᚗcomᚋarsmnᚋf
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14054,6 +15017,15 @@ func (ec *executionContext) marshalOCheckIssue8962ᚕᚖgithubᚗcomᚋarsmnᚋf
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOCheckIssue8962ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐCheckIssue896(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14061,13 +15033,18 @@ func (ec *executionContext) marshalOCheckIssue8962ᚕᚖgithubᚗcomᚋarsmnᚋf
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14094,6 +15071,15 @@ func (ec *executionContext) marshalOCheckIssue8962ᚕᚖgithubᚗcomᚋarsmnᚋf
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNCheckIssue8962ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐCheckIssue896(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14126,6 +15112,13 @@ func (ec *executionContext) marshalODefaultScalarImplementation2ᚖstring(ctx co
 	return graphql.MarshalString(*v)
 }
 
+func (ec *executionContext) marshalODeferModel2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐDeferModel(ctx context.Context, sel ast.SelectionSet, v *DeferModel) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._DeferModel(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalOEmbeddedCase12ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐEmbeddedCase1(ctx context.Context, sel ast.SelectionSet, v *EmbeddedCase1) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -14326,13 +15319,18 @@ func (ec *executionContext) marshalOOuterObject2ᚕᚕᚖgithubᚗcomᚋarsmnᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14359,6 +15357,15 @@ func (ec *executionContext) marshalOOuterObject2ᚕᚕᚖgithubᚗcomᚋarsmnᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOOuterObject2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐOuterObject(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14366,13 +15373,18 @@ func (ec *executionContext) marshalOOuterObject2ᚕᚖgithubᚗcomᚋarsmnᚋfas
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14399,6 +15411,15 @@ func (ec *executionContext) marshalOOuterObject2ᚕᚖgithubᚗcomᚋarsmnᚋfas
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOOuterObject2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐOuterObject(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14466,13 +15487,18 @@ func (ec *executionContext) marshalOShape2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋco
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14499,6 +15525,15 @@ func (ec *executionContext) marshalOShape2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋco
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOShape2githubᚗcomᚋarsmnᚋfastgqlᚋcodegenᚋtestserverᚐShape(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14546,11 +15581,25 @@ func (ec *executionContext) marshalOString2ᚕstringᚄ(ctx context.Context, sel
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNString2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14582,11 +15631,25 @@ func (ec *executionContext) marshalOString2ᚕᚖstring(ctx context.Context, sel
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalOString2ᚖstring(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOString2ᚖstring(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14677,13 +15740,18 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14710,6 +15778,15 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__EnumValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐEnumValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14717,13 +15794,18 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14750,6 +15832,15 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Field2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐField(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14757,13 +15848,18 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14790,6 +15886,15 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -14804,13 +15909,18 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -14837,6 +15947,15 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
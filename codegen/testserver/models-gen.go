@@ -64,6 +64,12 @@ type ContentUser struct {
 
 func (ContentUser) IsContentChild() {}
 
+type DeferModel struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
 type Dog struct {
 	Species  string `json:"species"`
 	DogBreed string `json:"dogBreed"`
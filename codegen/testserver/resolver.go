@@ -8,6 +8,7 @@ import (
 	introspection1 "github.com/sujamess/fastgql/codegen/testserver/introspection"
 	invalid_packagename "github.com/sujamess/fastgql/codegen/testserver/invalid-packagename"
 	"github.com/sujamess/fastgql/codegen/testserver/otherpkg"
+	"github.com/sujamess/fastgql/graphql"
 )
 
 type Resolver struct{}
@@ -132,6 +133,10 @@ func (r *queryResolver) Overlapping(ctx context.Context) (*OverlappingFields, er
 	panic("not implemented")
 }
 
+func (r *queryResolver) DeferCase(ctx context.Context) (*DeferModel, error) {
+	panic("not implemented")
+}
+
 func (r *queryResolver) DirectiveArg(ctx context.Context, arg string) (*string, error) {
 	panic("not implemented")
 }
@@ -320,6 +325,10 @@ func (r *subscriptionResolver) Issue896b(ctx context.Context) (<-chan []*CheckIs
 	panic("not implemented")
 }
 
+func (r *subscriptionResolver) Events(ctx context.Context) (<-chan graphql.SubscriptionEvent, error) {
+	panic("not implemented")
+}
+
 func (r *userResolver) Friends(ctx context.Context, obj *User) ([]*User, error) {
 	panic("not implemented")
 }
@@ -8,6 +8,7 @@ import (
 	introspection1 "github.com/sujamess/fastgql/codegen/testserver/introspection"
 	invalid_packagename "github.com/sujamess/fastgql/codegen/testserver/invalid-packagename"
 	"github.com/sujamess/fastgql/codegen/testserver/otherpkg"
+	"github.com/sujamess/fastgql/graphql"
 )
 
 type Stub struct {
@@ -60,6 +61,7 @@ type Stub struct {
 		Autobind                         func(ctx context.Context) (*Autobind, error)
 		DeprecatedField                  func(ctx context.Context) (string, error)
 		Overlapping                      func(ctx context.Context) (*OverlappingFields, error)
+		DeferCase                        func(ctx context.Context) (*DeferModel, error)
 		DirectiveArg                     func(ctx context.Context, arg string) (*string, error)
 		DirectiveNullableArg             func(ctx context.Context, arg *int, arg2 *int, arg3 *string) (*string, error)
 		DirectiveInputNullable           func(ctx context.Context, arg *InputDirectives) (*string, error)
@@ -109,6 +111,7 @@ type Stub struct {
 		DirectiveDouble        func(ctx context.Context) (<-chan *string, error)
 		DirectiveUnimplemented func(ctx context.Context) (<-chan *string, error)
 		Issue896b              func(ctx context.Context) (<-chan []*CheckIssue896, error)
+		Events                 func(ctx context.Context) (<-chan graphql.SubscriptionEvent, error)
 	}
 	UserResolver struct {
 		Friends func(ctx context.Context, obj *User) ([]*User, error)
@@ -283,6 +286,9 @@ func (r *stubQuery) DeprecatedField(ctx context.Context) (string, error) {
 func (r *stubQuery) Overlapping(ctx context.Context) (*OverlappingFields, error) {
 	return r.QueryResolver.Overlapping(ctx)
 }
+func (r *stubQuery) DeferCase(ctx context.Context) (*DeferModel, error) {
+	return r.QueryResolver.DeferCase(ctx)
+}
 func (r *stubQuery) DirectiveArg(ctx context.Context, arg string) (*string, error) {
 	return r.QueryResolver.DirectiveArg(ctx, arg)
 }
@@ -427,6 +433,9 @@ func (r *stubSubscription) DirectiveUnimplemented(ctx context.Context) (<-chan *
 func (r *stubSubscription) Issue896b(ctx context.Context) (<-chan []*CheckIssue896, error) {
 	return r.SubscriptionResolver.Issue896b(ctx)
 }
+func (r *stubSubscription) Events(ctx context.Context) (<-chan graphql.SubscriptionEvent, error) {
+	return r.SubscriptionResolver.Events(ctx)
+}
 
 type stubUser struct{ *Stub }
 
@@ -2,6 +2,7 @@ package testserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sort"
@@ -68,6 +69,21 @@ func TestSubscriptions(t *testing.T) {
 		return res, nil
 	}
 
+	resolvers.SubscriptionResolver.Events = func(ctx context.Context) (<-chan graphql.SubscriptionEvent, error) {
+		res := make(chan graphql.SubscriptionEvent, 5)
+		res <- graphql.SubscriptionEvent{Value: "first"}
+		res <- graphql.SubscriptionEvent{Err: errors.New("missed an event")}
+		res <- graphql.SubscriptionEvent{Value: 42}
+		res <- graphql.SubscriptionEvent{Value: "second"}
+		res <- graphql.SubscriptionEvent{Err: errors.New("source is gone"), Fatal: true}
+
+		go func() {
+			<-ctx.Done()
+			close(res)
+		}()
+		return res, nil
+	}
+
 	srv := handler.NewDefaultServer(
 		NewExecutableSchema(Config{Resolvers: resolvers}),
 	)
@@ -138,4 +154,32 @@ func TestSubscriptions(t *testing.T) {
 		require.Equal(t, "strings = []interface {}{\"hello\", \"world\"}", msg.resp.InitPayload)
 		sub.Close()
 	})
+
+	for _, subprotocol := range []string{"graphql-ws", "graphql-transport-ws"} {
+		t.Run("events carry errors over "+subprotocol, func(t *testing.T) {
+			sub := c.Websocket(`subscription { events }`, client.WebsocketSubprotocol(subprotocol))
+			defer sub.Close()
+
+			var resp struct {
+				Events *string
+			}
+
+			require.NoError(t, sub.Next(&resp))
+			require.Equal(t, "first", *resp.Events)
+
+			resp.Events = nil
+			err := sub.Next(&resp)
+			require.EqualError(t, err, `[{"message":"missed an event","path":["events"]}]`)
+			require.Nil(t, resp.Events)
+
+			err = sub.Next(&resp)
+			require.EqualError(t, err, `[{"message":"unexpected type int from subscription event, should be string","path":["events"]}]`)
+
+			require.NoError(t, sub.Next(&resp))
+			require.Equal(t, "second", *resp.Events)
+
+			err = sub.Next(&resp)
+			require.EqualError(t, err, `[{"message":"source is gone","path":["events"]}]`)
+		})
+	}
 }
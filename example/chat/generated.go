@@ -9,6 +9,7 @@ import (
 	"io"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sujamess/fastgql/graphql"
@@ -91,7 +92,7 @@ func (e *executableSchema) Schema() *ast.Schema {
 }
 
 func (e *executableSchema) Complexity(typeName, field string, childComplexity int, rawArgs map[string]interface{}) (int, bool) {
-	ec := executionContext{nil, e}
+	ec := executionContext{nil, e, 0, 0, nil}
 	_ = ec
 	switch typeName + "." + field {
 
@@ -179,14 +180,14 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e}
+	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
 	first := true
 
 	switch rc.Operation.Operation {
 	case ast.Query:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Query(ctx, rc.Operation.SelectionSet)
@@ -194,13 +195,14 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 	case ast.Mutation:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Mutation(ctx, rc.Operation.SelectionSet)
@@ -208,10 +210,13 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 	case ast.Subscription:
+		// subscriptions deliver every event as a whole, @defer and @stream are ignored
+		ec.deferredResults = nil
 		next := ec._subscriptionMiddleware(ctx, rc.Operation, func(ctx context.Context) (interface{}, error) {
 			return ec._Subscription(ctx, rc.Operation.SelectionSet), nil
 		})
@@ -226,9 +231,14 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			}
 			data.MarshalGQL(&buf)
 
-			return &graphql.Response{
+			response := &graphql.Response{
 				Data: buf.Bytes(),
 			}
+			if event, ok := data.(*graphql.EventMarshaler); ok {
+				response.Errors = event.Errors
+				response.Fatal = event.Fatal
+			}
+			return response
 		}
 
 	default:
@@ -239,6 +249,116 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 type executionContext struct {
 	*graphql.OperationContext
 	*executableSchema
+	deferred        int32
+	pendingDeferred int32
+	deferredResults chan graphql.DeferredResult
+}
+
+// deferFields sets the fields of @defer fragments apart, unless the operation delivers its result as a whole.
+func (ec *executionContext) deferFields(fields []graphql.CollectedField) ([]graphql.CollectedField, []graphql.DeferredGroup) {
+	if ec.deferredResults == nil {
+		return fields, nil
+	}
+	return graphql.SplitDeferredFields(fields)
+}
+
+// stream returns the @stream arguments of the list field being resolved, or nil if its items are all part of the
+// current payload.
+func (ec *executionContext) stream(ctx context.Context) *graphql.Stream {
+	if ec.deferredResults == nil {
+		return nil
+	}
+	return graphql.GetStream(ctx)
+}
+
+// processDeferredGroup resolves a deferred group in the background, its result is sent as an incremental payload.
+func (ec *executionContext) processDeferredGroup(ctx context.Context, group graphql.DeferredGroup, f func(ctx context.Context) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, 1)
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		ctx := graphql.WithFreshResponseContext(ctx)
+		ec.sendDeferred(ctx, graphql.DeferredResult{
+			Path:   path,
+			Label:  group.Label,
+			Result: ec.resolveDeferred(ctx, f),
+			Errors: graphql.GetErrors(ctx),
+		})
+	}()
+}
+
+// processStream resolves the list items from index start onwards in the background, each of them is sent as an
+// incremental payload in order.
+func (ec *executionContext) processStream(ctx context.Context, stream *graphql.Stream, start, end int, f func(ctx context.Context, i int) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, int32(end-start))
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		for i := start; i < end; i++ {
+			i := i
+			ctx := graphql.WithFreshResponseContext(ctx)
+			ec.sendDeferred(ctx, graphql.DeferredResult{
+				Path:   append(path[:len(path):len(path)], ast.PathIndex(i)),
+				Label:  stream.Label,
+				Result: ec.resolveDeferred(ctx, func(ctx context.Context) graphql.Marshaler { return f(ctx, i) }),
+				Errors: graphql.GetErrors(ctx),
+			})
+		}
+	}()
+}
+
+func (ec *executionContext) resolveDeferred(ctx context.Context, f func(ctx context.Context) graphql.Marshaler) (res graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			res = graphql.Null
+		}
+	}()
+	return f(ctx)
+}
+
+func (ec *executionContext) sendDeferred(ctx context.Context, result graphql.DeferredResult) {
+	select {
+	case ec.deferredResults <- result:
+	case <-ctx.Done():
+	}
+}
+
+// hasNext reports whether incremental payloads are still to come, it is nil for operations that deferred nothing.
+func (ec *executionContext) hasNext() *bool {
+	if atomic.LoadInt32(&ec.deferred) == 0 {
+		return nil
+	}
+	hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
+	return &hasNext
+}
+
+// nextDeferredResponse waits for the next deferred result and wraps it in an incremental payload.
+func (ec *executionContext) nextDeferredResponse(ctx context.Context) *graphql.Response {
+	if atomic.LoadInt32(&ec.pendingDeferred) == 0 {
+		return nil
+	}
+
+	var result graphql.DeferredResult
+	select {
+	case result = <-ec.deferredResults:
+	case <-ctx.Done():
+		return nil
+	}
+	atomic.AddInt32(&ec.pendingDeferred, -1)
+
+	var buf bytes.Buffer
+	result.Result.MarshalGQL(&buf)
+
+	return &graphql.Response{
+		Errors:  result.Errors,
+		Data:    buf.Bytes(),
+		Label:   result.Label,
+		Path:    result.Path,
+		HasNext: ec.hasNext(),
+	}
 }
 
 func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
@@ -1972,6 +2092,7 @@ var chatroomImplementors = []string{"Chatroom"}
 
 func (ec *executionContext) _Chatroom(ctx context.Context, sel ast.SelectionSet, obj *Chatroom) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, chatroomImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -1997,6 +2118,12 @@ func (ec *executionContext) _Chatroom(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Chatroom(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2004,6 +2131,7 @@ var messageImplementors = []string{"Message"}
 
 func (ec *executionContext) _Message(ctx context.Context, sel ast.SelectionSet, obj *Message) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, messageImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2039,6 +2167,12 @@ func (ec *executionContext) _Message(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Message(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2046,6 +2180,7 @@ var mutationImplementors = []string{"Mutation"}
 
 func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, mutationImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Mutation",
@@ -2070,6 +2205,12 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Mutation(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -2077,6 +2218,7 @@ var queryImplementors = []string{"Query"}
 
 func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Query",
@@ -2111,6 +2253,12 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Query(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -2138,6 +2286,7 @@ var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __DirectiveImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2170,6 +2319,12 @@ func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Directive(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2177,6 +2332,7 @@ var __EnumValueImplementors = []string{"__EnumValue"}
 
 func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.EnumValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __EnumValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2206,6 +2362,12 @@ func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___EnumValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2213,6 +2375,7 @@ var __FieldImplementors = []string{"__Field"}
 
 func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet, obj *introspection.Field) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __FieldImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2252,6 +2415,12 @@ func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Field(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2259,6 +2428,7 @@ var __InputValueImplementors = []string{"__InputValue"}
 
 func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.InputValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __InputValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2288,6 +2458,12 @@ func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___InputValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2295,6 +2471,7 @@ var __SchemaImplementors = []string{"__Schema"}
 
 func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet, obj *introspection.Schema) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __SchemaImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2329,6 +2506,12 @@ func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Schema(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2336,6 +2519,7 @@ var __TypeImplementors = []string{"__Type"}
 
 func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2372,6 +2556,12 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Type(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2414,13 +2604,18 @@ func (ec *executionContext) marshalNMessage2githubᚗcomᚋarsmnᚋfastgqlᚋexa
 }
 
 func (ec *executionContext) marshalNMessage2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋchatᚐMessageᚄ(ctx context.Context, sel ast.SelectionSet, v []Message) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2447,6 +2642,15 @@ func (ec *executionContext) marshalNMessage2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNMessage2githubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋchatᚐMessage(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2495,13 +2699,18 @@ func (ec *executionContext) marshalN__Directive2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirectiveᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Directive) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2528,6 +2737,15 @@ func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Directive2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirective(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2568,13 +2786,18 @@ func (ec *executionContext) unmarshalN__DirectiveLocation2ᚕstringᚄ(ctx conte
 }
 
 func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2601,6 +2824,15 @@ func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__DirectiveLocation2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2617,13 +2849,18 @@ func (ec *executionContext) marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.InputValue) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2650,6 +2887,15 @@ func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2658,13 +2904,18 @@ func (ec *executionContext) marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgrap
 }
 
 func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Type) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2691,6 +2942,15 @@ func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2778,13 +3038,18 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2811,6 +3076,15 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__EnumValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐEnumValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2818,13 +3092,18 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2851,6 +3130,15 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Field2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐField(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2858,13 +3146,18 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2891,6 +3184,15 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2905,13 +3207,18 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2938,6 +3245,15 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -86,7 +86,7 @@ func (e *executableSchema) Schema() *ast.Schema {
 }
 
 func (e *executableSchema) Complexity(typeName, field string, childComplexity int, rawArgs map[string]interface{}) (int, bool) {
-	ec := executionContext{nil, e}
+	ec := executionContext{nil, e, 0, 0, nil}
 	_ = ec
 	switch typeName + "." + field {
 
@@ -164,14 +164,14 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e}
+	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
 	first := true
 
 	switch rc.Operation.Operation {
 	case ast.Query:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Query(ctx, rc.Operation.SelectionSet)
@@ -179,13 +179,14 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 	case ast.Mutation:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Mutation(ctx, rc.Operation.SelectionSet)
@@ -193,7 +194,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 
@@ -205,6 +207,116 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 type executionContext struct {
 	*graphql.OperationContext
 	*executableSchema
+	deferred        int32
+	pendingDeferred int32
+	deferredResults chan graphql.DeferredResult
+}
+
+// deferFields sets the fields of @defer fragments apart, unless the operation delivers its result as a whole.
+func (ec *executionContext) deferFields(fields []graphql.CollectedField) ([]graphql.CollectedField, []graphql.DeferredGroup) {
+	if ec.deferredResults == nil {
+		return fields, nil
+	}
+	return graphql.SplitDeferredFields(fields)
+}
+
+// stream returns the @stream arguments of the list field being resolved, or nil if its items are all part of the
+// current payload.
+func (ec *executionContext) stream(ctx context.Context) *graphql.Stream {
+	if ec.deferredResults == nil {
+		return nil
+	}
+	return graphql.GetStream(ctx)
+}
+
+// processDeferredGroup resolves a deferred group in the background, its result is sent as an incremental payload.
+func (ec *executionContext) processDeferredGroup(ctx context.Context, group graphql.DeferredGroup, f func(ctx context.Context) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, 1)
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		ctx := graphql.WithFreshResponseContext(ctx)
+		ec.sendDeferred(ctx, graphql.DeferredResult{
+			Path:   path,
+			Label:  group.Label,
+			Result: ec.resolveDeferred(ctx, f),
+			Errors: graphql.GetErrors(ctx),
+		})
+	}()
+}
+
+// processStream resolves the list items from index start onwards in the background, each of them is sent as an
+// incremental payload in order.
+func (ec *executionContext) processStream(ctx context.Context, stream *graphql.Stream, start, end int, f func(ctx context.Context, i int) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, int32(end-start))
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		for i := start; i < end; i++ {
+			i := i
+			ctx := graphql.WithFreshResponseContext(ctx)
+			ec.sendDeferred(ctx, graphql.DeferredResult{
+				Path:   append(path[:len(path):len(path)], ast.PathIndex(i)),
+				Label:  stream.Label,
+				Result: ec.resolveDeferred(ctx, func(ctx context.Context) graphql.Marshaler { return f(ctx, i) }),
+				Errors: graphql.GetErrors(ctx),
+			})
+		}
+	}()
+}
+
+func (ec *executionContext) resolveDeferred(ctx context.Context, f func(ctx context.Context) graphql.Marshaler) (res graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			res = graphql.Null
+		}
+	}()
+	return f(ctx)
+}
+
+func (ec *executionContext) sendDeferred(ctx context.Context, result graphql.DeferredResult) {
+	select {
+	case ec.deferredResults <- result:
+	case <-ctx.Done():
+	}
+}
+
+// hasNext reports whether incremental payloads are still to come, it is nil for operations that deferred nothing.
+func (ec *executionContext) hasNext() *bool {
+	if atomic.LoadInt32(&ec.deferred) == 0 {
+		return nil
+	}
+	hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
+	return &hasNext
+}
+
+// nextDeferredResponse waits for the next deferred result and wraps it in an incremental payload.
+func (ec *executionContext) nextDeferredResponse(ctx context.Context) *graphql.Response {
+	if atomic.LoadInt32(&ec.pendingDeferred) == 0 {
+		return nil
+	}
+
+	var result graphql.DeferredResult
+	select {
+	case result = <-ec.deferredResults:
+	case <-ctx.Done():
+		return nil
+	}
+	atomic.AddInt32(&ec.pendingDeferred, -1)
+
+	var buf bytes.Buffer
+	result.Result.MarshalGQL(&buf)
+
+	return &graphql.Response{
+		Errors:  result.Errors,
+		Data:    buf.Bytes(),
+		Label:   result.Label,
+		Path:    result.Path,
+		HasNext: ec.hasNext(),
+	}
 }
 
 func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
@@ -1847,6 +1959,7 @@ var mutationImplementors = []string{"Mutation"}
 
 func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, mutationImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Mutation",
@@ -1871,6 +1984,12 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Mutation(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -1878,6 +1997,7 @@ var queryImplementors = []string{"Query"}
 
 func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Query",
@@ -1915,6 +2035,12 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Query(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -1922,6 +2048,7 @@ var todoImplementors = []string{"Todo"}
 
 func (ec *executionContext) _Todo(ctx context.Context, sel ast.SelectionSet, obj *Todo) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, todoImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -1971,6 +2098,12 @@ func (ec *executionContext) _Todo(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Todo(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -1978,6 +2111,7 @@ var userImplementors = []string{"User"}
 
 func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj *User) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, userImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2003,6 +2137,12 @@ func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._User(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2010,6 +2150,7 @@ var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __DirectiveImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2042,6 +2183,12 @@ func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Directive(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2049,6 +2196,7 @@ var __EnumValueImplementors = []string{"__EnumValue"}
 
 func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.EnumValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __EnumValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2078,6 +2226,12 @@ func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___EnumValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2085,6 +2239,7 @@ var __FieldImplementors = []string{"__Field"}
 
 func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet, obj *introspection.Field) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __FieldImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2124,6 +2279,12 @@ func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Field(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2131,6 +2292,7 @@ var __InputValueImplementors = []string{"__InputValue"}
 
 func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.InputValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __InputValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2160,6 +2322,12 @@ func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___InputValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2167,6 +2335,7 @@ var __SchemaImplementors = []string{"__Schema"}
 
 func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet, obj *introspection.Schema) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __SchemaImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2201,6 +2370,12 @@ func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Schema(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2208,6 +2383,7 @@ var __TypeImplementors = []string{"__Type"}
 
 func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2244,6 +2420,12 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Type(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2321,13 +2503,18 @@ func (ec *executionContext) marshalNTodo2githubᚗcomᚋarsmnᚋfastgqlᚋexampl
 }
 
 func (ec *executionContext) marshalNTodo2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋconfigᚐTodoᚄ(ctx context.Context, sel ast.SelectionSet, v []*Todo) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2354,6 +2541,15 @@ func (ec *executionContext) marshalNTodo2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNTodo2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋconfigᚐTodo(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2382,13 +2578,18 @@ func (ec *executionContext) marshalN__Directive2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirectiveᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Directive) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2415,6 +2616,15 @@ func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Directive2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirective(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2455,13 +2665,18 @@ func (ec *executionContext) unmarshalN__DirectiveLocation2ᚕstringᚄ(ctx conte
 }
 
 func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2488,6 +2703,15 @@ func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__DirectiveLocation2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2504,13 +2728,18 @@ func (ec *executionContext) marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.InputValue) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2537,6 +2766,15 @@ func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2545,13 +2783,18 @@ func (ec *executionContext) marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgrap
 }
 
 func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Type) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2578,6 +2821,15 @@ func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2667,11 +2919,25 @@ func (ec *executionContext) marshalOString2ᚕstringᚄ(ctx context.Context, sel
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNString2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2694,13 +2960,18 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2727,6 +2998,15 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__EnumValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐEnumValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2734,13 +3014,18 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2767,6 +3052,15 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Field2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐField(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2774,13 +3068,18 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2807,6 +3106,15 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2821,13 +3129,18 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2854,6 +3167,15 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
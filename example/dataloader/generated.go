@@ -99,7 +99,7 @@ func (e *executableSchema) Schema() *ast.Schema {
 }
 
 func (e *executableSchema) Complexity(typeName, field string, childComplexity int, rawArgs map[string]interface{}) (int, bool) {
-	ec := executionContext{nil, e}
+	ec := executionContext{nil, e, 0, 0, nil}
 	_ = ec
 	switch typeName + "." + field {
 
@@ -224,14 +224,14 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e}
+	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
 	first := true
 
 	switch rc.Operation.Operation {
 	case ast.Query:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Query(ctx, rc.Operation.SelectionSet)
@@ -239,7 +239,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 
@@ -251,6 +252,116 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 type executionContext struct {
 	*graphql.OperationContext
 	*executableSchema
+	deferred        int32
+	pendingDeferred int32
+	deferredResults chan graphql.DeferredResult
+}
+
+// deferFields sets the fields of @defer fragments apart, unless the operation delivers its result as a whole.
+func (ec *executionContext) deferFields(fields []graphql.CollectedField) ([]graphql.CollectedField, []graphql.DeferredGroup) {
+	if ec.deferredResults == nil {
+		return fields, nil
+	}
+	return graphql.SplitDeferredFields(fields)
+}
+
+// stream returns the @stream arguments of the list field being resolved, or nil if its items are all part of the
+// current payload.
+func (ec *executionContext) stream(ctx context.Context) *graphql.Stream {
+	if ec.deferredResults == nil {
+		return nil
+	}
+	return graphql.GetStream(ctx)
+}
+
+// processDeferredGroup resolves a deferred group in the background, its result is sent as an incremental payload.
+func (ec *executionContext) processDeferredGroup(ctx context.Context, group graphql.DeferredGroup, f func(ctx context.Context) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, 1)
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		ctx := graphql.WithFreshResponseContext(ctx)
+		ec.sendDeferred(ctx, graphql.DeferredResult{
+			Path:   path,
+			Label:  group.Label,
+			Result: ec.resolveDeferred(ctx, f),
+			Errors: graphql.GetErrors(ctx),
+		})
+	}()
+}
+
+// processStream resolves the list items from index start onwards in the background, each of them is sent as an
+// incremental payload in order.
+func (ec *executionContext) processStream(ctx context.Context, stream *graphql.Stream, start, end int, f func(ctx context.Context, i int) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, int32(end-start))
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		for i := start; i < end; i++ {
+			i := i
+			ctx := graphql.WithFreshResponseContext(ctx)
+			ec.sendDeferred(ctx, graphql.DeferredResult{
+				Path:   append(path[:len(path):len(path)], ast.PathIndex(i)),
+				Label:  stream.Label,
+				Result: ec.resolveDeferred(ctx, func(ctx context.Context) graphql.Marshaler { return f(ctx, i) }),
+				Errors: graphql.GetErrors(ctx),
+			})
+		}
+	}()
+}
+
+func (ec *executionContext) resolveDeferred(ctx context.Context, f func(ctx context.Context) graphql.Marshaler) (res graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			res = graphql.Null
+		}
+	}()
+	return f(ctx)
+}
+
+func (ec *executionContext) sendDeferred(ctx context.Context, result graphql.DeferredResult) {
+	select {
+	case ec.deferredResults <- result:
+	case <-ctx.Done():
+	}
+}
+
+// hasNext reports whether incremental payloads are still to come, it is nil for operations that deferred nothing.
+func (ec *executionContext) hasNext() *bool {
+	if atomic.LoadInt32(&ec.deferred) == 0 {
+		return nil
+	}
+	hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
+	return &hasNext
+}
+
+// nextDeferredResponse waits for the next deferred result and wraps it in an incremental payload.
+func (ec *executionContext) nextDeferredResponse(ctx context.Context) *graphql.Response {
+	if atomic.LoadInt32(&ec.pendingDeferred) == 0 {
+		return nil
+	}
+
+	var result graphql.DeferredResult
+	select {
+	case result = <-ec.deferredResults:
+	case <-ctx.Done():
+		return nil
+	}
+	atomic.AddInt32(&ec.pendingDeferred, -1)
+
+	var buf bytes.Buffer
+	result.Result.MarshalGQL(&buf)
+
+	return &graphql.Response{
+		Errors:  result.Errors,
+		Data:    buf.Bytes(),
+		Label:   result.Label,
+		Path:    result.Path,
+		HasNext: ec.hasNext(),
+	}
 }
 
 func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
@@ -2082,6 +2193,7 @@ var addressImplementors = []string{"Address"}
 
 func (ec *executionContext) _Address(ctx context.Context, sel ast.SelectionSet, obj *Address) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, addressImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2112,6 +2224,12 @@ func (ec *executionContext) _Address(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Address(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2119,6 +2237,7 @@ var customerImplementors = []string{"Customer"}
 
 func (ec *executionContext) _Customer(ctx context.Context, sel ast.SelectionSet, obj *Customer) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, customerImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2166,6 +2285,12 @@ func (ec *executionContext) _Customer(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Customer(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2173,6 +2298,7 @@ var itemImplementors = []string{"Item"}
 
 func (ec *executionContext) _Item(ctx context.Context, sel ast.SelectionSet, obj *Item) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, itemImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2193,6 +2319,12 @@ func (ec *executionContext) _Item(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Item(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2200,6 +2332,7 @@ var orderImplementors = []string{"Order"}
 
 func (ec *executionContext) _Order(ctx context.Context, sel ast.SelectionSet, obj *Order) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, orderImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2241,6 +2374,12 @@ func (ec *executionContext) _Order(ctx context.Context, sel ast.SelectionSet, ob
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Order(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2248,6 +2387,7 @@ var queryImplementors = []string{"Query"}
 
 func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Query",
@@ -2304,6 +2444,12 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Query(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -2311,6 +2457,7 @@ var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __DirectiveImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2343,6 +2490,12 @@ func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Directive(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2350,6 +2503,7 @@ var __EnumValueImplementors = []string{"__EnumValue"}
 
 func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.EnumValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __EnumValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2379,6 +2533,12 @@ func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___EnumValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2386,6 +2546,7 @@ var __FieldImplementors = []string{"__Field"}
 
 func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet, obj *introspection.Field) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __FieldImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2425,6 +2586,12 @@ func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Field(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2432,6 +2599,7 @@ var __InputValueImplementors = []string{"__InputValue"}
 
 func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.InputValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __InputValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2461,6 +2629,12 @@ func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___InputValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2468,6 +2642,7 @@ var __SchemaImplementors = []string{"__Schema"}
 
 func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet, obj *introspection.Schema) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __SchemaImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2502,6 +2677,12 @@ func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Schema(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2509,6 +2690,7 @@ var __TypeImplementors = []string{"__Type"}
 
 func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2545,6 +2727,12 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Type(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2662,13 +2850,18 @@ func (ec *executionContext) marshalN__Directive2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirectiveᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Directive) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2695,6 +2888,15 @@ func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Directive2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirective(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2735,13 +2937,18 @@ func (ec *executionContext) unmarshalN__DirectiveLocation2ᚕstringᚄ(ctx conte
 }
 
 func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2768,6 +2975,15 @@ func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__DirectiveLocation2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2784,13 +3000,18 @@ func (ec *executionContext) marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.InputValue) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2817,6 +3038,15 @@ func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2825,13 +3055,18 @@ func (ec *executionContext) marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgrap
 }
 
 func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Type) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2858,6 +3093,15 @@ func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2921,13 +3165,18 @@ func (ec *executionContext) marshalOCustomer2ᚕᚕᚖgithubᚗcomᚋarsmnᚋfas
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2954,6 +3203,15 @@ func (ec *executionContext) marshalOCustomer2ᚕᚕᚖgithubᚗcomᚋarsmnᚋfas
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOCustomer2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋdataloaderᚐCustomerᚄ(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2961,13 +3219,18 @@ func (ec *executionContext) marshalOCustomer2ᚕᚖgithubᚗcomᚋarsmnᚋfastgq
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2994,6 +3257,15 @@ func (ec *executionContext) marshalOCustomer2ᚕᚖgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNCustomer2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋdataloaderᚐCustomer(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3025,11 +3297,25 @@ func (ec *executionContext) marshalOInt2ᚕintᚄ(ctx context.Context, sel ast.S
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalNInt2int(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNInt2int(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3061,11 +3347,25 @@ func (ec *executionContext) marshalOInt2ᚕᚕint(ctx context.Context, sel ast.S
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalOInt2ᚕintᚄ(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalOInt2ᚕintᚄ(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3073,13 +3373,18 @@ func (ec *executionContext) marshalOItem2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -3106,6 +3411,15 @@ func (ec *executionContext) marshalOItem2ᚕᚖgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNItem2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋdataloaderᚐItem(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3113,13 +3427,18 @@ func (ec *executionContext) marshalOOrder2ᚕᚖgithubᚗcomᚋarsmnᚋfastgql
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -3146,6 +3465,15 @@ func (ec *executionContext) marshalOOrder2ᚕᚖgithubᚗcomᚋarsmnᚋfastgql
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalNOrder2ᚖgithubᚗcomᚋarsmnᚋfastgqlᚋexampleᚋdataloaderᚐOrder(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3177,13 +3505,18 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -3210,6 +3543,15 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__EnumValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐEnumValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3217,13 +3559,18 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -3250,6 +3597,15 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Field2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐField(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3257,13 +3613,18 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -3290,6 +3651,15 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -3304,13 +3674,18 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -3337,6 +3712,15 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -83,7 +83,7 @@ func (e *executableSchema) Schema() *ast.Schema {
 }
 
 func (e *executableSchema) Complexity(typeName, field string, childComplexity int, rawArgs map[string]interface{}) (int, bool) {
-	ec := executionContext{nil, e}
+	ec := executionContext{nil, e, 0, 0, nil}
 	_ = ec
 	switch typeName + "." + field {
 
@@ -152,14 +152,14 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	rc := graphql.GetOperationContext(ctx)
-	ec := executionContext{rc, e}
+	ec := executionContext{rc, e, 0, 0, make(chan graphql.DeferredResult)}
 	first := true
 
 	switch rc.Operation.Operation {
 	case ast.Query:
 		return func(ctx context.Context) *graphql.Response {
 			if !first {
-				return nil
+				return ec.nextDeferredResponse(ctx)
 			}
 			first = false
 			data := ec._Query(ctx, rc.Operation.SelectionSet)
@@ -167,7 +167,8 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			data.MarshalGQL(&buf)
 
 			return &graphql.Response{
-				Data: buf.Bytes(),
+				Data:    buf.Bytes(),
+				HasNext: ec.hasNext(),
 			}
 		}
 
@@ -179,6 +180,116 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 type executionContext struct {
 	*graphql.OperationContext
 	*executableSchema
+	deferred        int32
+	pendingDeferred int32
+	deferredResults chan graphql.DeferredResult
+}
+
+// deferFields sets the fields of @defer fragments apart, unless the operation delivers its result as a whole.
+func (ec *executionContext) deferFields(fields []graphql.CollectedField) ([]graphql.CollectedField, []graphql.DeferredGroup) {
+	if ec.deferredResults == nil {
+		return fields, nil
+	}
+	return graphql.SplitDeferredFields(fields)
+}
+
+// stream returns the @stream arguments of the list field being resolved, or nil if its items are all part of the
+// current payload.
+func (ec *executionContext) stream(ctx context.Context) *graphql.Stream {
+	if ec.deferredResults == nil {
+		return nil
+	}
+	return graphql.GetStream(ctx)
+}
+
+// processDeferredGroup resolves a deferred group in the background, its result is sent as an incremental payload.
+func (ec *executionContext) processDeferredGroup(ctx context.Context, group graphql.DeferredGroup, f func(ctx context.Context) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, 1)
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		ctx := graphql.WithFreshResponseContext(ctx)
+		ec.sendDeferred(ctx, graphql.DeferredResult{
+			Path:   path,
+			Label:  group.Label,
+			Result: ec.resolveDeferred(ctx, f),
+			Errors: graphql.GetErrors(ctx),
+		})
+	}()
+}
+
+// processStream resolves the list items from index start onwards in the background, each of them is sent as an
+// incremental payload in order.
+func (ec *executionContext) processStream(ctx context.Context, stream *graphql.Stream, start, end int, f func(ctx context.Context, i int) graphql.Marshaler) {
+	atomic.AddInt32(&ec.deferred, 1)
+	atomic.AddInt32(&ec.pendingDeferred, int32(end-start))
+	path := graphql.GetFieldContext(ctx).Path()
+
+	go func() {
+		for i := start; i < end; i++ {
+			i := i
+			ctx := graphql.WithFreshResponseContext(ctx)
+			ec.sendDeferred(ctx, graphql.DeferredResult{
+				Path:   append(path[:len(path):len(path)], ast.PathIndex(i)),
+				Label:  stream.Label,
+				Result: ec.resolveDeferred(ctx, func(ctx context.Context) graphql.Marshaler { return f(ctx, i) }),
+				Errors: graphql.GetErrors(ctx),
+			})
+		}
+	}()
+}
+
+func (ec *executionContext) resolveDeferred(ctx context.Context, f func(ctx context.Context) graphql.Marshaler) (res graphql.Marshaler) {
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			res = graphql.Null
+		}
+	}()
+	return f(ctx)
+}
+
+func (ec *executionContext) sendDeferred(ctx context.Context, result graphql.DeferredResult) {
+	select {
+	case ec.deferredResults <- result:
+	case <-ctx.Done():
+	}
+}
+
+// hasNext reports whether incremental payloads are still to come, it is nil for operations that deferred nothing.
+func (ec *executionContext) hasNext() *bool {
+	if atomic.LoadInt32(&ec.deferred) == 0 {
+		return nil
+	}
+	hasNext := atomic.LoadInt32(&ec.pendingDeferred) > 0
+	return &hasNext
+}
+
+// nextDeferredResponse waits for the next deferred result and wraps it in an incremental payload.
+func (ec *executionContext) nextDeferredResponse(ctx context.Context) *graphql.Response {
+	if atomic.LoadInt32(&ec.pendingDeferred) == 0 {
+		return nil
+	}
+
+	var result graphql.DeferredResult
+	select {
+	case result = <-ec.deferredResults:
+	case <-ctx.Done():
+		return nil
+	}
+	atomic.AddInt32(&ec.pendingDeferred, -1)
+
+	var buf bytes.Buffer
+	result.Result.MarshalGQL(&buf)
+
+	return &graphql.Response{
+		Errors:  result.Errors,
+		Data:    buf.Bytes(),
+		Label:   result.Label,
+		Path:    result.Path,
+		HasNext: ec.hasNext(),
+	}
 }
 
 func (ec *executionContext) introspectSchema() (*introspection.Schema, error) {
@@ -1763,6 +1874,7 @@ var entityImplementors = []string{"Entity"}
 
 func (ec *executionContext) _Entity(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, entityImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Entity",
@@ -1796,6 +1908,12 @@ func (ec *executionContext) _Entity(ctx context.Context, sel ast.SelectionSet) g
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Entity(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -1803,6 +1921,7 @@ var queryImplementors = []string{"Query"}
 
 func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, queryImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
 		Object: "Query",
@@ -1865,6 +1984,12 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._Query(ctx, group.SelectionSet)
+		})
+	}
 	return out
 }
 
@@ -1872,6 +1997,7 @@ var userImplementors = []string{"User", "_Entity"}
 
 func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj *model.User) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, userImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -1897,6 +2023,12 @@ func (ec *executionContext) _User(ctx context.Context, sel ast.SelectionSet, obj
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec._User(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -1904,6 +2036,7 @@ var _ServiceImplementors = []string{"_Service"}
 
 func (ec *executionContext) __Service(ctx context.Context, sel ast.SelectionSet, obj *fedruntime.Service) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, _ServiceImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -1921,6 +2054,12 @@ func (ec *executionContext) __Service(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.__Service(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -1928,6 +2067,7 @@ var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __DirectiveImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -1960,6 +2100,12 @@ func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Directive(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -1967,6 +2113,7 @@ var __EnumValueImplementors = []string{"__EnumValue"}
 
 func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.EnumValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __EnumValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -1996,6 +2143,12 @@ func (ec *executionContext) ___EnumValue(ctx context.Context, sel ast.SelectionS
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___EnumValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2003,6 +2156,7 @@ var __FieldImplementors = []string{"__Field"}
 
 func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet, obj *introspection.Field) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __FieldImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2042,6 +2196,12 @@ func (ec *executionContext) ___Field(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Field(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2049,6 +2209,7 @@ var __InputValueImplementors = []string{"__InputValue"}
 
 func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.SelectionSet, obj *introspection.InputValue) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __InputValueImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2078,6 +2239,12 @@ func (ec *executionContext) ___InputValue(ctx context.Context, sel ast.Selection
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___InputValue(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2085,6 +2252,7 @@ var __SchemaImplementors = []string{"__Schema"}
 
 func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet, obj *introspection.Schema) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __SchemaImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2119,6 +2287,12 @@ func (ec *executionContext) ___Schema(ctx context.Context, sel ast.SelectionSet,
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Schema(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2126,6 +2300,7 @@ var __TypeImplementors = []string{"__Type"}
 
 func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, obj *introspection.Type) graphql.Marshaler {
 	fields := graphql.CollectFields(ec.OperationContext, sel, __TypeImplementors)
+	fields, deferred := ec.deferFields(fields)
 
 	out := graphql.NewFieldSet(fields)
 	var invalids uint32
@@ -2162,6 +2337,12 @@ func (ec *executionContext) ___Type(ctx context.Context, sel ast.SelectionSet, o
 	if invalids > 0 {
 		return graphql.Null
 	}
+	for _, group := range deferred {
+		group := group
+		ec.processDeferredGroup(ctx, group, func(ctx context.Context) graphql.Marshaler {
+			return ec.___Type(ctx, group.SelectionSet, obj)
+		})
+	}
 	return out
 }
 
@@ -2271,22 +2452,41 @@ func (ec *executionContext) unmarshalN_Any2ᚕmapᚄ(ctx context.Context, v inte
 }
 
 func (ec *executionContext) marshalN_Any2ᚕmapᚄ(ctx context.Context, sel ast.SelectionSet, v []map[string]interface{}) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
-	for i := range v {
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
+	for i := range v[:n] {
 		ret[i] = ec.marshalN_Any2map(ctx, sel, v[i])
 	}
 
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN_Any2map(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
 func (ec *executionContext) marshalN_Entity2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋpluginᚋfederationᚋfedruntimeᚐEntity(ctx context.Context, sel ast.SelectionSet, v []fedruntime.Entity) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2313,6 +2513,15 @@ func (ec *executionContext) marshalN_Entity2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalO_Entity2githubᚗcomᚋarsmnᚋfastgqlᚋpluginᚋfederationᚋfedruntimeᚐEntity(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2340,13 +2549,18 @@ func (ec *executionContext) marshalN__Directive2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirectiveᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Directive) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2373,6 +2587,15 @@ func (ec *executionContext) marshalN__Directive2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Directive2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐDirective(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2413,13 +2636,18 @@ func (ec *executionContext) unmarshalN__DirectiveLocation2ᚕstringᚄ(ctx conte
 }
 
 func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2446,6 +2674,15 @@ func (ec *executionContext) marshalN__DirectiveLocation2ᚕstringᚄ(ctx context
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__DirectiveLocation2string(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2462,13 +2699,18 @@ func (ec *executionContext) marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgql
 }
 
 func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValueᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.InputValue) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2495,6 +2737,15 @@ func (ec *executionContext) marshalN__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2503,13 +2754,18 @@ func (ec *executionContext) marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgrap
 }
 
 func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐTypeᚄ(ctx context.Context, sel ast.SelectionSet, v []introspection.Type) graphql.Marshaler {
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2536,6 +2792,15 @@ func (ec *executionContext) marshalN__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2630,13 +2895,18 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2663,6 +2933,15 @@ func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋarsmnᚋfastgq
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__EnumValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐEnumValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2670,13 +2949,18 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2703,6 +2987,15 @@ func (ec *executionContext) marshalO__Field2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋ
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Field2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐField(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2710,13 +3003,18 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2743,6 +3041,15 @@ func (ec *executionContext) marshalO__InputValue2ᚕgithubᚗcomᚋarsmnᚋfastg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__InputValue2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐInputValue(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -2757,13 +3064,18 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 	if v == nil {
 		return graphql.Null
 	}
-	ret := make(graphql.Array, len(v))
+	n := len(v)
+	stream := ec.stream(ctx)
+	if stream != nil && stream.InitialCount < n {
+		n = stream.InitialCount
+	}
+	ret := make(graphql.Array, n)
 	var wg sync.WaitGroup
-	isLen1 := len(v) == 1
+	isLen1 := n == 1
 	if !isLen1 {
-		wg.Add(len(v))
+		wg.Add(n)
 	}
-	for i := range v {
+	for i := range v[:n] {
 		i := i
 		fc := &graphql.FieldContext{
 			Index:  &i,
@@ -2790,6 +3102,15 @@ func (ec *executionContext) marshalO__Type2ᚕgithubᚗcomᚋarsmnᚋfastgqlᚋg
 
 	}
 	wg.Wait()
+	if n < len(v) {
+		ec.processStream(ctx, stream, n, len(v), func(ctx context.Context, i int) graphql.Marshaler {
+			ctx = graphql.WithFieldContext(ctx, &graphql.FieldContext{
+				Index:  &i,
+				Result: &v[i],
+			})
+			return ec.marshalN__Type2githubᚗcomᚋarsmnᚋfastgqlᚋgraphqlᚋintrospectionᚐType(ctx, sel, v[i])
+		})
+	}
 	return ret
 }
 
@@ -36,6 +36,17 @@ func WithResponseContext(ctx context.Context, presenterFunc ErrorPresenterFunc,
 	})
 }
 
+// WithFreshResponseContext returns a context with an empty response context that shares the error presenter and
+// recover func of the one already in ctx. Deferred results collect their errors in it, apart from the payload that
+// was being built when they were deferred.
+func WithFreshResponseContext(ctx context.Context) context.Context {
+	c := getResponseContext(ctx)
+	return context.WithValue(ctx, resultCtx, &responseContext{
+		errorPresenter: c.errorPresenter,
+		recover:        c.recover,
+	})
+}
+
 // AddErrorf writes a formatted error to the client, first passing it through the error presenter.
 func AddErrorf(ctx context.Context, format string, args ...interface{}) {
 	AddError(ctx, fmt.Errorf(format, args...))
@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DeferredGroup is the set of fields selected under @defer fragments sharing a label. The selection set only holds
+// those fields, so executing it against the same object yields the incremental payload.
+type DeferredGroup struct {
+	Label        string
+	SelectionSet ast.SelectionSet
+}
+
+// DeferredResult is the outcome of a deferred group or of a single streamed list item, ready to be sent as an
+// incremental payload.
+type DeferredResult struct {
+	Path   ast.Path
+	Label  string
+	Result Marshaler
+	Errors gqlerror.List
+}
+
+// SplitDeferredFields sets the deferred fields apart from the ones that belong to the current payload. Deferred
+// fields are grouped by label, in the order they were first selected.
+func SplitDeferredFields(fields []CollectedField) ([]CollectedField, []DeferredGroup) {
+	var groups []DeferredGroup
+	current := make([]CollectedField, 0, len(fields))
+	for _, field := range fields {
+		if field.Deferrable == nil {
+			current = append(current, field)
+			continue
+		}
+
+		group := -1
+		for i := range groups {
+			if groups[i].Label == field.Deferrable.Label {
+				group = i
+				break
+			}
+		}
+		if group == -1 {
+			groups = append(groups, DeferredGroup{Label: field.Deferrable.Label})
+			group = len(groups) - 1
+		}
+
+		// the merged selections of the field become its selection set, so the group can be collected again
+		// without picking up the @defer directive of the fragment it came from
+		f := *field.Field
+		f.SelectionSet = field.Selections
+		groups[group].SelectionSet = append(groups[group].SelectionSet, &f)
+	}
+
+	return current, groups
+}
+
+// Stream holds the arguments of the @stream directive on a list field.
+type Stream struct {
+	Label        string
+	InitialCount int
+}
+
+// GetStream returns the arguments of an active @stream directive on the list field being resolved, or nil if the
+// field isn't streamed. Nested lists are never streamed, only the outermost list of the field is.
+func GetStream(ctx context.Context) *Stream {
+	fc := GetFieldContext(ctx)
+	if fc == nil || fc.Index != nil || fc.Field.Field == nil {
+		return nil
+	}
+
+	d := fc.Field.Directives.ForName("stream")
+	if d == nil {
+		return nil
+	}
+
+	variables := GetOperationContext(ctx).Variables
+	if d.Arguments.ForName("if") != nil && !resolveIfArgument(d, variables) {
+		return nil
+	}
+
+	var s Stream
+	if arg := d.Arguments.ForName("label"); arg != nil {
+		value, err := arg.Value.Value(variables)
+		if err != nil {
+			panic(err)
+		}
+		s.Label, _ = value.(string)
+	}
+	if arg := d.Arguments.ForName("initialCount"); arg != nil {
+		value, err := arg.Value.Value(variables)
+		if err != nil {
+			panic(err)
+		}
+		if n, ok := value.(int64); ok && n > 0 {
+			s.InitialCount = int(n)
+		}
+	}
+	return &s
+}
@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestSplitDeferredFields(t *testing.T) {
+	deferDirective := func(label string) ast.DirectiveList {
+		return ast.DirectiveList{{
+			Name: "defer",
+			Arguments: ast.ArgumentList{{
+				Name:  "label",
+				Value: &ast.Value{Kind: ast.StringValue, Raw: label},
+			}},
+		}}
+	}
+
+	rc := &OperationContext{}
+
+	t.Run("fields of deferred fragments are grouped by label", func(t *testing.T) {
+		fields := CollectFields(rc, ast.SelectionSet{
+			&ast.Field{Name: "a", Alias: "a"},
+			&ast.InlineFragment{
+				Directives:   deferDirective("slow"),
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "b", Alias: "b"}},
+			},
+			&ast.InlineFragment{
+				Directives:   deferDirective("slower"),
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "c", Alias: "c"}},
+			},
+			&ast.InlineFragment{
+				Directives:   deferDirective("slow"),
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "d", Alias: "d"}},
+			},
+		}, nil)
+
+		current, groups := SplitDeferredFields(fields)
+		require.Len(t, current, 1)
+		require.Equal(t, "a", current[0].Name)
+
+		require.Len(t, groups, 2)
+		require.Equal(t, "slow", groups[0].Label)
+		require.Len(t, groups[0].SelectionSet, 2)
+		require.Equal(t, "b", groups[0].SelectionSet[0].(*ast.Field).Name)
+		require.Equal(t, "d", groups[0].SelectionSet[1].(*ast.Field).Name)
+		require.Equal(t, "slower", groups[1].Label)
+	})
+
+	t.Run("fields also selected without defer are not deferred", func(t *testing.T) {
+		fields := CollectFields(rc, ast.SelectionSet{
+			&ast.InlineFragment{
+				Directives:   deferDirective("slow"),
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "a", Alias: "a"}},
+			},
+			&ast.Field{Name: "a", Alias: "a"},
+		}, nil)
+
+		current, groups := SplitDeferredFields(fields)
+		require.Len(t, current, 1)
+		require.Empty(t, groups)
+	})
+
+	t.Run("defer can be disabled", func(t *testing.T) {
+		fields := CollectFields(rc, ast.SelectionSet{
+			&ast.InlineFragment{
+				Directives: ast.DirectiveList{{
+					Name: "defer",
+					Arguments: ast.ArgumentList{{
+						Name:  "if",
+						Value: &ast.Value{Kind: ast.BooleanValue, Raw: "false"},
+					}},
+				}},
+				SelectionSet: ast.SelectionSet{&ast.Field{Name: "a", Alias: "a"}},
+			},
+		}, nil)
+
+		current, groups := SplitDeferredFields(fields)
+		require.Len(t, current, 1)
+		require.Empty(t, groups)
+	})
+}
@@ -14,6 +14,8 @@ const (
 	KindProtocol ErrorKind = iota
 	// user errors, 200s in http, GQL_DATA in websocket
 	KindUser
+	// operations refused until the client slows down, 429s in http, GQL_ERROR in websocket
+	KindRateLimited
 )
 
 var codeType = map[string]ErrorKind{
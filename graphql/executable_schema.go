@@ -36,17 +36,20 @@ func collectFields(reqCtx *OperationContext, selSet ast.SelectionSet, satisfies
 				return CollectedField{Field: sel}
 			})
 
+			// a field selected outside of any deferred fragment is part of the initial payload
+			f.Deferrable = nil
 			f.Selections = append(f.Selections, sel.SelectionSet...)
 		case *ast.InlineFragment:
 			if !shouldIncludeNode(sel.Directives, reqCtx.Variables) {
 				continue
 			}
-			if len(satisfies) > 0 && !instanceOf(sel.TypeCondition, satisfies) {
+			// an inline fragment without a type condition applies to the enclosing type
+			if len(satisfies) > 0 && sel.TypeCondition != "" && !instanceOf(sel.TypeCondition, satisfies) {
 				continue
 			}
+			d := deferrable(sel.Directives, reqCtx.Variables)
 			for _, childField := range collectFields(reqCtx, sel.SelectionSet, satisfies, visited) {
-				f := getOrCreateAndAppendField(&groupedFields, childField.Name, childField.Alias, childField.ObjectDefinition, func() CollectedField { return childField })
-				f.Selections = append(f.Selections, childField.Selections...)
+				appendChildField(&groupedFields, childField, d)
 			}
 
 		case *ast.FragmentSpread:
@@ -69,9 +72,9 @@ func collectFields(reqCtx *OperationContext, selSet ast.SelectionSet, satisfies
 				continue
 			}
 
+			d := deferrable(sel.Directives, reqCtx.Variables)
 			for _, childField := range collectFields(reqCtx, fragment.SelectionSet, satisfies, visited) {
-				f := getOrCreateAndAppendField(&groupedFields, childField.Name, childField.Alias, childField.ObjectDefinition, func() CollectedField { return childField })
-				f.Selections = append(f.Selections, childField.Selections...)
+				appendChildField(&groupedFields, childField, d)
 			}
 		default:
 			panic(fmt.Errorf("unsupported %T", sel))
@@ -85,6 +88,27 @@ type CollectedField struct {
 	*ast.Field
 
 	Selections ast.SelectionSet
+
+	// Deferrable is set when the field was only selected under fragments marked with @defer.
+	Deferrable *Deferrable
+}
+
+// Deferrable holds the arguments of the @defer directive a field was selected under.
+type Deferrable struct {
+	Label string
+}
+
+// appendChildField merges a field collected from a fragment into c. Fields of a deferred fragment stay deferred
+// unless they are also selected without @defer.
+func appendChildField(c *[]CollectedField, childField CollectedField, d *Deferrable) {
+	if childField.Deferrable == nil {
+		childField.Deferrable = d
+	}
+	f := getOrCreateAndAppendField(c, childField.Name, childField.Alias, childField.ObjectDefinition, func() CollectedField { return childField })
+	if childField.Deferrable == nil {
+		f.Deferrable = nil
+	}
+	f.Selections = append(f.Selections, childField.Selections...)
 }
 
 func instanceOf(val string, satisfies []string) bool {
@@ -127,6 +151,28 @@ func shouldIncludeNode(directives ast.DirectiveList, variables map[string]interf
 	return !skip && include
 }
 
+// deferrable returns the arguments of an active @defer directive, or nil when the fragment isn't deferred.
+func deferrable(directives ast.DirectiveList, variables map[string]interface{}) *Deferrable {
+	d := directives.ForName("defer")
+	if d == nil {
+		return nil
+	}
+
+	if d.Arguments.ForName("if") != nil && !resolveIfArgument(d, variables) {
+		return nil
+	}
+
+	var label string
+	if arg := d.Arguments.ForName("label"); arg != nil {
+		value, err := arg.Value.Value(variables)
+		if err != nil {
+			panic(err)
+		}
+		label, _ = value.(string)
+	}
+	return &Deferrable{Label: label}
+}
+
 func resolveIfArgument(d *ast.Directive, variables map[string]interface{}) bool {
 	arg := d.Arguments.ForName("if")
 	if arg == nil {
@@ -47,7 +47,7 @@ func (e *Executor) CreateOperationContext(ctx context.Context, params *graphql.R
 			OperationStart: graphql.GetStartTime(ctx),
 		},
 	}
-	graphql.WithOperationContext(ctx, rc)
+	ctx = graphql.WithOperationContext(ctx, rc)
 
 	for _, p := range e.ext.operationParameterMutators {
 		if err := p.MutateOperationParameters(ctx, params); err != nil {
@@ -59,14 +59,16 @@ func (e *Executor) CreateOperationContext(ctx context.Context, params *graphql.R
 	rc.OperationName = params.OperationName
 
 	var listErr gqlerror.List
-	rc.Doc, listErr = e.parseQuery(ctx, &rc.Stats, params.Query)
+	rc.Doc, listErr = e.parseQuery(ctx, &rc.Stats, params)
 	if len(listErr) != 0 {
 		return rc, listErr
 	}
 
 	rc.Operation = rc.Doc.Operations.ForName(params.OperationName)
 	if rc.Operation == nil {
-		return rc, gqlerror.List{gqlerror.Errorf("operation %s not found", params.OperationName)}
+		err := gqlerror.Errorf("operation %s not found", params.OperationName)
+		errcode.Set(err, errcode.ValidationFailed)
+		return rc, gqlerror.List{err}
 	}
 
 	var err *gqlerror.Error
@@ -158,9 +160,19 @@ func (e *Executor) SetRecoverFunc(f graphql.RecoverFunc) {
 //
 // NOTE: This should NOT look at variables, they will change per request. It should only parse and validate
 // the raw query string.
-func (e *Executor) parseQuery(ctx context.Context, stats *graphql.Stats, query string) (*ast.QueryDocument, gqlerror.List) {
+func (e *Executor) parseQuery(ctx context.Context, stats *graphql.Stats, params *graphql.RawParams) (*ast.QueryDocument, gqlerror.List) {
 	stats.Parsing.Start = graphql.Now()
 
+	// the document has been validated by the extension that set it
+	if params.Doc != nil {
+		now := graphql.Now()
+
+		stats.Parsing.End = now
+		stats.Validation.Start = now
+		return params.Doc, nil
+	}
+
+	query := params.Query
 	if doc, ok := e.queryCache.Get(ctx, query); ok {
 		now := graphql.Now()
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
@@ -25,6 +26,14 @@ type (
 		Variables     map[string]interface{} `json:"variables"`
 		Extensions    map[string]interface{} `json:"extensions"`
 
+		// DocumentID and ID are the ids Relay clients send in place of the query of a persisted operation
+		DocumentID string `json:"doc_id"`
+		ID         string `json:"id"`
+
+		// Doc is the document of Query, parsed and validated. OperationParameterMutators serving documents they
+		// have already validated set it, so the query isn't parsed again.
+		Doc *ast.QueryDocument `json:"-"`
+
 		ReadTime TraceTiming `json:"-"`
 	}
 
@@ -0,0 +1,323 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// CacheControl caches the responses of queries, following the @cacheControl hints of the schema the way Apollo
+// Server does. The directive has to be declared in the schema:
+//
+//	enum CacheControlScope { PUBLIC PRIVATE }
+//	directive @cacheControl(maxAge: Int, scope: CacheControlScope, inheritMaxAge: Boolean) on FIELD_DEFINITION | OBJECT | INTERFACE | UNION
+//
+// The policy of an operation is the lowest maxAge of the fields it resolved, and is private when one of them is.
+// Fields returning an object, an interface or a union, and the root fields, have a maxAge of DefaultMaxAge unless
+// they or their type have a hint. The other fields don't change the policy.
+//
+// The responses are cached when their policy has a maxAge, and sent with the Cache-Control header of their policy.
+// Mutations, subscriptions, responses with errors and the operations of the transports delivering several responses
+// are never cached.
+type CacheControl struct {
+	// Cache stores the responses, they are cached as JSON so they can be stored in shared caches.
+	Cache graphql.Cache
+
+	// Scope returns the key the private responses of a client are cached under, like its user ID. Private responses
+	// aren't cached when it is nil or returns an empty key.
+	Scope func(ctx context.Context, rc *graphql.OperationContext) string
+
+	// DefaultMaxAge sets the maxAge of the root fields, and of the fields returning an object, an interface or a
+	// union, without hints. Defaults to 0, which makes responses only cacheable when they select hinted fields.
+	DefaultMaxAge int
+
+	queryType string
+	hints     map[string]cacheHint
+}
+
+var _ interface {
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+	graphql.HandlerExtension
+} = &CacheControl{}
+
+const cacheControlCtx key = "cache_control"
+
+// cacheHint is what a field does to the policy of the operation.
+type cacheHint struct {
+	maxAge    int
+	hasMaxAge bool
+	inherit   bool
+	private   bool
+}
+
+// cachePolicy is the policy of an operation, restricted by every field it resolves.
+type cachePolicy struct {
+	mu        sync.Mutex
+	maxAge    int
+	hasMaxAge bool
+	private   bool
+}
+
+// cachedResponse is a response stored in the Cache.
+type cachedResponse struct {
+	Data    json.RawMessage `json:"data"`
+	Expires time.Time       `json:"expires"`
+	Private bool            `json:"private"`
+}
+
+func (c CacheControl) ExtensionName() string {
+	return "CacheControl"
+}
+
+func (c *CacheControl) Validate(schema graphql.ExecutableSchema) error {
+	if c.Cache == nil {
+		return fmt.Errorf("CacheControl.Cache can not be nil")
+	}
+	s := schema.Schema()
+	if s.Directives["cacheControl"] == nil {
+		return fmt.Errorf("CacheControl needs the schema to declare directive @cacheControl")
+	}
+
+	if s.Query != nil {
+		c.queryType = s.Query.Name
+	}
+	c.hints = map[string]cacheHint{}
+	for _, def := range s.Types {
+		for _, field := range def.Fields {
+			if hint, ok := c.fieldHint(s, field); ok {
+				c.hints[def.Name+"."+field.Name] = hint
+			}
+		}
+	}
+	return nil
+}
+
+// fieldHint merges the hint of field with the one of the type it returns, ok is false when the field doesn't change
+// the policy.
+func (c *CacheControl) fieldHint(schema *ast.Schema, field *ast.FieldDefinition) (hint cacheHint, ok bool) {
+	hint = parseCacheHint(field.Directives)
+
+	if returned := schema.Types[field.Type.Name()]; returned != nil && returned.IsCompositeType() {
+		typeHint := parseCacheHint(returned.Directives)
+		if !hint.hasMaxAge && !hint.inherit {
+			hint.maxAge, hint.hasMaxAge, hint.inherit = typeHint.maxAge, typeHint.hasMaxAge, typeHint.inherit
+		}
+		hint.private = hint.private || typeHint.private
+		if !hint.hasMaxAge && !hint.inherit {
+			hint.maxAge, hint.hasMaxAge = c.DefaultMaxAge, true
+		}
+	}
+
+	return hint, hint.hasMaxAge || hint.inherit || hint.private
+}
+
+func parseCacheHint(directives ast.DirectiveList) cacheHint {
+	var hint cacheHint
+	directive := directives.ForName("cacheControl")
+	if directive == nil {
+		return hint
+	}
+	if arg := directive.Arguments.ForName("maxAge"); arg != nil {
+		if maxAge, err := strconv.Atoi(arg.Value.Raw); err == nil {
+			hint.maxAge, hint.hasMaxAge = maxAge, true
+		}
+	}
+	if arg := directive.Arguments.ForName("scope"); arg != nil {
+		hint.private = arg.Value.Raw == "PRIVATE"
+	}
+	if arg := directive.Arguments.ForName("inheritMaxAge"); arg != nil {
+		hint.inherit = arg.Value.Raw == "true"
+	}
+	return hint
+}
+
+func (c *CacheControl) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	rc := graphql.GetOperationContext(ctx)
+	if graphql.IsStreaming(ctx) {
+		return next(ctx)
+	}
+	if rc.Operation.Operation != ast.Query {
+		setCacheControl(ctx, 0, false)
+		return next(ctx)
+	}
+
+	var scope string
+	if c.Scope != nil {
+		scope = c.Scope(ctx, rc)
+	}
+
+	publicKey, privateKey := c.cacheKeys(rc, scope)
+	if resp, ok := c.lookup(ctx, publicKey); ok {
+		return graphql.OneShot(resp)
+	}
+	if resp, ok := c.lookup(ctx, privateKey); ok {
+		return graphql.OneShot(resp)
+	}
+
+	policy := &cachePolicy{}
+	responses := next(context.WithValue(ctx, cacheControlCtx, policy))
+
+	return func(ctx context.Context) *graphql.Response {
+		resp := responses(ctx)
+		if resp == nil {
+			return nil
+		}
+
+		policy.mu.Lock()
+		maxAge, private := policy.maxAge, policy.private
+		if !policy.hasMaxAge {
+			maxAge = 0
+		}
+		policy.mu.Unlock()
+
+		// incremental responses are only complete once all of them have been sent
+		if len(resp.Errors) != 0 || resp.HasNext != nil || maxAge <= 0 || (private && scope == "") {
+			setCacheControl(ctx, 0, false)
+			return resp
+		}
+
+		cached, err := json.Marshal(&cachedResponse{
+			Data:    resp.Data,
+			Expires: graphql.Now().Add(time.Duration(maxAge) * time.Second),
+			Private: private,
+		})
+		if err == nil {
+			if private {
+				c.Cache.Add(ctx, privateKey, cached)
+			} else {
+				c.Cache.Add(ctx, publicKey, cached)
+			}
+		}
+		setCacheControl(ctx, maxAge, private)
+		return resp
+	}
+}
+
+func (c *CacheControl) InterceptField(ctx context.Context, next graphql.Resolver) (res interface{}, err error) {
+	policy, ok := ctx.Value(cacheControlCtx).(*cachePolicy)
+	if !ok {
+		return next(ctx)
+	}
+
+	fc := graphql.GetFieldContext(ctx)
+	hint, ok := c.hints[fc.Object+"."+fc.Field.Name]
+	if fc.Object == c.queryType && !hint.hasMaxAge && !hint.inherit {
+		hint.maxAge, hint.hasMaxAge, ok = c.DefaultMaxAge, true, true
+	}
+	if ok {
+		policy.restrict(hint)
+	}
+
+	return next(ctx)
+}
+
+func (p *cachePolicy) restrict(hint cacheHint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if hint.hasMaxAge && (!p.hasMaxAge || hint.maxAge < p.maxAge) {
+		p.maxAge, p.hasMaxAge = hint.maxAge, true
+	}
+	p.private = p.private || hint.private
+}
+
+// cacheKeys returns the keys the response of rc is cached under when it is public, and when it is private to scope.
+// They don't depend on the formatting of the query.
+func (c *CacheControl) cacheKeys(rc *graphql.OperationContext, scope string) (publicKey, privateKey string) {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(rc.Doc)
+	buf.WriteString(rc.Operation.Name)
+	if variables, err := json.Marshal(rc.Variables); err == nil {
+		buf.Write(variables)
+	}
+
+	hash := sha256.Sum256(buf.Bytes())
+	publicKey = "public:" + hex.EncodeToString(hash[:])
+	if scope != "" {
+		privateKey = "private:" + scope + ":" + hex.EncodeToString(hash[:])
+	}
+	return publicKey, privateKey
+}
+
+// lookup returns the cached response for key, when it hasn't expired yet.
+func (c *CacheControl) lookup(ctx context.Context, key string) (*graphql.Response, bool) {
+	if key == "" {
+		return nil, false
+	}
+	value, ok := c.Cache.Get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, false
+	}
+
+	var cached cachedResponse
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, false
+	}
+	maxAge := int(math.Ceil(cached.Expires.Sub(graphql.Now()).Seconds()))
+	if maxAge <= 0 {
+		return nil, false
+	}
+
+	setCacheControl(ctx, maxAge, cached.Private)
+	return &graphql.Response{Data: cached.Data}, true
+}
+
+// setCacheControl sets the Cache-Control header of the response, a maxAge of 0 making it uncacheable. The operations
+// of a batch share their response, which gets the most restrictive of their policies.
+func setCacheControl(ctx context.Context, maxAge int, private bool) {
+	graphql.UpdateRequestCtx(ctx, func(rctx *fasthttp.RequestCtx) {
+		if previous := rctx.Response.Header.Peek("Cache-Control"); len(previous) != 0 {
+			previousMaxAge, previousPrivate := parseCacheControl(string(previous))
+			maxAge = min(maxAge, previousMaxAge)
+			private = private || previousPrivate
+		}
+
+		switch {
+		case maxAge <= 0:
+			rctx.Response.Header.Set("Cache-Control", "no-store")
+		case private:
+			rctx.Response.Header.Set("Cache-Control", "max-age="+strconv.Itoa(maxAge)+", private")
+		default:
+			rctx.Response.Header.Set("Cache-Control", "max-age="+strconv.Itoa(maxAge)+", public")
+		}
+	})
+}
+
+// parseCacheControl reads the policy of a Cache-Control header, a header without max-age has a maxAge of 0.
+func parseCacheControl(header string) (maxAge int, private bool) {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.TrimSpace(directive)
+		switch {
+		case strings.HasPrefix(directive, "max-age="):
+			maxAge, _ = strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		case directive == "private":
+			private = true
+		}
+	}
+	return maxAge, private
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,163 @@
+package extension_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler"
+	"github.com/sujamess/fastgql/graphql/handler/extension"
+	"github.com/sujamess/fastgql/graphql/handler/lru"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestCacheControl(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	graphql.Now = func() time.Time { return now }
+	defer func() { graphql.Now = time.Now }()
+
+	user := ""
+	h, executions := hintedServer(&extension.CacheControl{
+		// the operations of a batch use the cache concurrently
+		Cache: lru.New(100),
+		Scope: func(ctx context.Context, rc *graphql.OperationContext) string {
+			return user
+		},
+	})
+
+	t.Run("public responses are cached with the lowest maxAge", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ posts { title } version }"}`)
+		require.Equal(t, `{"data":{"executions":1}}`, string(resp.Body()))
+		require.Equal(t, "max-age=30, public", string(resp.Header.Peek("Cache-Control")))
+
+		now = now.Add(10 * time.Second)
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"{\n  posts {\n    title\n  }\n  version\n}"}`)
+		require.Equal(t, `{"data":{"executions":1}}`, string(resp.Body()))
+		require.Equal(t, "max-age=20, public", string(resp.Header.Peek("Cache-Control")))
+		require.Equal(t, 1, *executions)
+
+		now = now.Add(20 * time.Second)
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ posts { title } version }"}`)
+		require.Equal(t, `{"data":{"executions":2}}`, string(resp.Body()))
+	})
+
+	t.Run("fields returning objects inherit the hint of their type", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ posts { title } }"}`)
+		require.Equal(t, "max-age=60, public", string(resp.Header.Peek("Cache-Control")))
+	})
+
+	t.Run("unhinted root fields are not cacheable", func(t *testing.T) {
+		*executions = 0
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ posts { title } uncached }"}`)
+		require.Equal(t, "no-store", string(resp.Header.Peek("Cache-Control")))
+
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ posts { title } uncached }"}`)
+		require.Equal(t, 2, *executions)
+	})
+
+	t.Run("private responses are cached per scope", func(t *testing.T) {
+		*executions = 0
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ me { name } }"}`)
+		require.Equal(t, "no-store", string(resp.Header.Peek("Cache-Control")))
+
+		user = "alice"
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ me { name } }"}`)
+		require.Equal(t, "max-age=10, private", string(resp.Header.Peek("Cache-Control")))
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ me { name } }"}`)
+		require.Equal(t, 2, *executions)
+
+		user = "bob"
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ me { name } }"}`)
+		require.Equal(t, 3, *executions)
+	})
+
+	t.Run("variables are part of the key", func(t *testing.T) {
+		*executions = 0
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"query($id: Int!) { post(id: $id) { title } }","variables":{"id":1}}`)
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"query($id: Int!) { post(id: $id) { title } }","variables":{"id":1}}`)
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"query($id: Int!) { post(id: $id) { title } }","variables":{"id":2}}`)
+		require.Equal(t, 2, *executions)
+	})
+
+	t.Run("mutations and errors are not cached", func(t *testing.T) {
+		*executions = 0
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"mutation { vote }"}`)
+		require.Equal(t, "no-store", string(resp.Header.Peek("Cache-Control")))
+
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ version broken }"}`)
+		require.Equal(t, "no-store", string(resp.Header.Peek("Cache-Control")))
+		doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ version broken }"}`)
+		require.Equal(t, 3, *executions)
+	})
+
+	t.Run("batches get the most restrictive policy", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `[{"query":"{ posts { title } }"},{"query":"{ version }"}]`)
+		require.Equal(t, "max-age=30, public", string(resp.Header.Peek("Cache-Control")))
+	})
+}
+
+// hintedServer serves a schema with @cacheControl hints, it runs the field middleware of the fields an operation
+// selects and counts its executions.
+func hintedServer(ext graphql.HandlerExtension) (*handler.Server, *int) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		enum CacheControlScope { PUBLIC PRIVATE }
+		directive @cacheControl(maxAge: Int, scope: CacheControlScope, inheritMaxAge: Boolean) on FIELD_DEFINITION | OBJECT | INTERFACE | UNION
+
+		type Query {
+			posts: [Post!]! @cacheControl(maxAge: 60)
+			post(id: Int!): Post
+			me: User!
+			version: String! @cacheControl(maxAge: 30)
+			uncached: String!
+			broken: String @cacheControl(maxAge: 30)
+		}
+		type Mutation { vote: Int! @cacheControl(maxAge: 30) }
+		type Post @cacheControl(maxAge: 120) { title: String! }
+		type User @cacheControl(maxAge: 10, scope: PRIVATE) { name: String! }
+	`})
+
+	executions := 0
+	h := handler.New(&graphql.ExecutableSchemaMock{
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			rc := graphql.GetOperationContext(ctx)
+			executions++
+
+			var errs gqlerror.List
+			var resolve func(ctx context.Context, object string, selectionSet ast.SelectionSet)
+			resolve = func(ctx context.Context, object string, selectionSet ast.SelectionSet) {
+				for _, field := range graphql.CollectFields(rc, selectionSet, []string{object}) {
+					ctx := graphql.WithFieldContext(ctx, &graphql.FieldContext{Object: object, Field: field})
+					_, _ = rc.ResolverMiddleware(ctx, func(ctx context.Context) (interface{}, error) {
+						return nil, nil
+					})
+					if field.Name == "broken" {
+						errs = append(errs, gqlerror.Errorf("broken"))
+					}
+					resolve(ctx, field.Definition.Type.Name(), field.Selections)
+				}
+			}
+			root := schema.Query.Name
+			if rc.Operation.Operation == ast.Mutation {
+				root = schema.Mutation.Name
+			}
+			resolve(ctx, root, rc.Operation.SelectionSet)
+
+			return graphql.OneShot(&graphql.Response{
+				Data:   []byte(fmt.Sprintf(`{"executions":%d}`, executions)),
+				Errors: errs,
+			})
+		},
+		SchemaFunc: func() *ast.Schema {
+			return schema
+		},
+	})
+	h.AddTransport(&transport.POST{})
+	h.Use(ext)
+	return h, &executions
+}
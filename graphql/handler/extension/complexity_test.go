@@ -63,6 +63,24 @@ func TestHandlerComplexity(t *testing.T) {
 	})
 }
 
+func TestHandlerComplexityBatch(t *testing.T) {
+	h := testserver.New()
+	h.Use(&extension.ComplexityLimit{
+		Func: func(ctx context.Context, rc *graphql.OperationContext) int {
+			if rc.RawQuery == "{ ok: name }" {
+				return 4
+			}
+			return 2
+		},
+	})
+	h.AddTransport(&transport.POST{})
+
+	h.SetCalculatedComplexity(4)
+	resp := doRequest(h.Handler(), "POST", "/graphql", `[{"query":"{ ok: name }"},{"query":"{ name }"}]`)
+	require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+	require.Equal(t, `[{"data":{"name":"test"}},{"errors":[{"message":"operation has complexity 4, which exceeds the limit of 2","extensions":{"code":"COMPLEXITY_LIMIT_EXCEEDED"}}],"data":null}]`, string(resp.Body()))
+}
+
 func TestFixedComplexity(t *testing.T) {
 	h := testserver.New()
 	h.Use(extension.FixedComplexityLimit(2))
@@ -0,0 +1,180 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+func init() {
+	errcode.RegisterErrorType(errDepthLimit, errcode.KindProtocol)
+}
+
+// DepthLimit allows you to define a limit on how deeply the fields of a query are nested
+//
+// Fragments don't add to the depth, the fields they select count at the level they are spread at. The introspection
+// fields, __schema and __type, are checked against IntrospectionLimit instead, as the query of introspection
+// clients is deeper than most.
+type DepthLimit struct {
+	Func func(ctx context.Context, rc *graphql.OperationContext) int
+
+	// IntrospectionLimit sets the depth allowed under the introspection fields. Defaults to 15, which the
+	// introspection query of GraphiQL fits in.
+	IntrospectionLimit int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = &DepthLimit{}
+
+const depthExtension = "DepthLimit"
+
+type DepthStats struct {
+	// The depth of the operation, introspection fields left out
+	Depth int
+
+	// The depth limit for this request returned by the extension func
+	DepthLimit int
+
+	// The depth of the introspection fields of the operation
+	IntrospectionDepth int
+}
+
+// FixedDepthLimit sets a depth limit that does not change
+func FixedDepthLimit(limit int) *DepthLimit {
+	return &DepthLimit{
+		Func: func(ctx context.Context, rc *graphql.OperationContext) int {
+			return limit
+		},
+	}
+}
+
+func (d DepthLimit) ExtensionName() string {
+	return depthExtension
+}
+
+func (d *DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	if d.Func == nil {
+		return fmt.Errorf("DepthLimit func can not be nil")
+	}
+	return nil
+}
+
+func (d DepthLimit) introspectionLimit() int {
+	if d.IntrospectionLimit == 0 {
+		return 15
+	}
+	return d.IntrospectionLimit
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	walker := depthWalker{fragments: map[string]int{}}
+
+	var depth, introspectionDepth int
+	for _, field := range walker.rootFields(op.SelectionSet) {
+		fieldDepth := 1 + walker.selectionSetDepth(field.SelectionSet)
+		if strings.HasPrefix(field.Name, "__") {
+			introspectionDepth = max(introspectionDepth, fieldDepth)
+		} else {
+			depth = max(depth, fieldDepth)
+		}
+	}
+
+	limit := d.Func(ctx, rc)
+
+	rc.Stats.SetExtension(depthExtension, &DepthStats{
+		Depth:              depth,
+		DepthLimit:         limit,
+		IntrospectionDepth: introspectionDepth,
+	})
+
+	if depth > limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	if introspectionDepth > d.introspectionLimit() {
+		err := gqlerror.Errorf("introspection has depth %d, which exceeds the limit of %d", introspectionDepth, d.introspectionLimit())
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+
+	return nil
+}
+
+func GetDepthStats(ctx context.Context) *DepthStats {
+	rc := graphql.GetOperationContext(ctx)
+	if rc == nil {
+		return nil
+	}
+
+	s, _ := rc.Stats.GetExtension(depthExtension).(*DepthStats)
+	return s
+}
+
+// depthWalker measures the depth of selection sets. The depth of a fragment doesn't depend on where it is spread, it
+// is only measured once so documents spreading fragments many times don't cost more to measure.
+type depthWalker struct {
+	fragments map[string]int
+}
+
+// rootFields returns the fields of the operation, including the ones selected through fragments.
+func (dw depthWalker) rootFields(selectionSet ast.SelectionSet) []*ast.Field {
+	var fields []*ast.Field
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			fields = append(fields, s)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				fields = append(fields, dw.rootFields(s.Definition.SelectionSet)...)
+			}
+		case *ast.InlineFragment:
+			fields = append(fields, dw.rootFields(s.SelectionSet)...)
+		}
+	}
+	return fields
+}
+
+func (dw depthWalker) selectionSetDepth(selectionSet ast.SelectionSet) int {
+	var depth int
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			depth = max(depth, 1+dw.selectionSetDepth(s.SelectionSet))
+
+		case *ast.FragmentSpread:
+			if s.Definition == nil {
+				continue
+			}
+			fragmentDepth, ok := dw.fragments[s.Name]
+			if !ok {
+				// validation rejects fragment cycles, the marker only guards against unvalidated documents
+				dw.fragments[s.Name] = 0
+				fragmentDepth = dw.selectionSetDepth(s.Definition.SelectionSet)
+				dw.fragments[s.Name] = fragmentDepth
+			}
+			depth = max(depth, fragmentDepth)
+
+		case *ast.InlineFragment:
+			depth = max(depth, dw.selectionSetDepth(s.SelectionSet))
+		}
+	}
+	return depth
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
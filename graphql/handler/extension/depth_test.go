@@ -0,0 +1,109 @@
+package extension_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler"
+	"github.com/sujamess/fastgql/graphql/handler/extension"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestHandlerDepth(t *testing.T) {
+	limit := &extension.DepthLimit{
+		Func: func(ctx context.Context, rc *graphql.OperationContext) int {
+			if rc.Operation.Name == "Deep" {
+				return 4
+			}
+			return 3
+		},
+		IntrospectionLimit: 5,
+	}
+	h := nestedServer(limit)
+
+	var stats *extension.DepthStats
+	h.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		stats = extension.GetDepthStats(ctx)
+		return next(ctx)
+	})
+
+	t.Run("below depth limit", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { friends { name } } }"}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"data":{}}`, string(resp.Body()))
+
+		require.Equal(t, 3, stats.DepthLimit)
+		require.Equal(t, 3, stats.Depth)
+	})
+
+	t.Run("above depth limit", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { friends { friends { name } } } }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"operation has depth 4, which exceeds the limit of 3","extensions":{"code":"DEPTH_LIMIT_EXCEEDED"}}],"data":null}`, string(resp.Body()))
+
+		require.Equal(t, 3, stats.DepthLimit)
+		require.Equal(t, 4, stats.Depth)
+	})
+
+	t.Run("fields selected through fragments", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ ...Root } fragment Root on Query { user { ...Friends } } fragment Friends on User { friends { ... on User { friends { name } } } }"}`)
+		require.Equal(t, `{"errors":[{"message":"operation has depth 4, which exceeds the limit of 3","extensions":{"code":"DEPTH_LIMIT_EXCEEDED"}}],"data":null}`, string(resp.Body()))
+
+		require.Equal(t, 4, stats.Depth)
+	})
+
+	t.Run("within dynamic depth limit", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"query Deep { user { friends { friends { name } } } }"}`)
+		require.Equal(t, `{"data":{}}`, string(resp.Body()))
+
+		require.Equal(t, 4, stats.DepthLimit)
+		require.Equal(t, 4, stats.Depth)
+	})
+
+	t.Run("introspection has its own limit", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { name } __schema { types { fields { type { name } } } } }"}`)
+		require.Equal(t, `{"data":{}}`, string(resp.Body()))
+
+		require.Equal(t, 2, stats.Depth)
+		require.Equal(t, 5, stats.IntrospectionDepth)
+
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ __schema { types { fields { type { ofType { name } } } } } }"}`)
+		require.Equal(t, `{"errors":[{"message":"introspection has depth 6, which exceeds the limit of 5","extensions":{"code":"DEPTH_LIMIT_EXCEEDED"}}],"data":null}`, string(resp.Body()))
+	})
+}
+
+func TestFixedDepthLimit(t *testing.T) {
+	h := nestedServer(extension.FixedDepthLimit(2))
+
+	resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { name } }"}`)
+	require.Equal(t, `{"data":{}}`, string(resp.Body()))
+
+	resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { friends { name } } }"}`)
+	require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+	require.Equal(t, `{"errors":[{"message":"operation has depth 3, which exceeds the limit of 2","extensions":{"code":"DEPTH_LIMIT_EXCEEDED"}}],"data":null}`, string(resp.Body()))
+}
+
+// nestedServer serves a schema whose fields can be nested as deep as a query wants, it never resolves them.
+func nestedServer(extension graphql.HandlerExtension) *handler.Server {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { user: User! }
+		type User { name: String! friends: [User!]! }
+	`})
+
+	h := handler.New(&graphql.ExecutableSchemaMock{
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			return graphql.OneShot(&graphql.Response{Data: []byte(`{}`)})
+		},
+		SchemaFunc: func() *ast.Schema {
+			return schema
+		},
+	})
+	h.AddTransport(&transport.POST{})
+	h.Use(extension)
+	return h
+}
@@ -0,0 +1,301 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/pubsub"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// LiveQuery keeps the queries marked with @live open on the transports delivering several responses, websocket and
+// SSE. Resolvers register the topics the data they return depends on with RegisterLiveTopics, and the query is
+// executed again once Invalidate is called with one of them. Results are only sent when they changed.
+//
+// The directive has to be declared in the schema:
+//
+//	directive @live on QUERY
+//
+// The other transports execute @live queries once, like any other query.
+type LiveQuery struct {
+	// Broker carries the invalidations, share an external one between servers so Invalidate reaches the live queries
+	// of all of them. Defaults to an in-memory pubsub.Hub.
+	Broker pubsub.Broker
+
+	// Throttle is the minimum time between two executions of a live query, the invalidations arriving in the meantime
+	// are handled by a single execution. Defaults to 1 second.
+	Throttle time.Duration
+}
+
+var _ interface {
+	graphql.OperationInterceptor
+	graphql.HandlerExtension
+} = &LiveQuery{}
+
+type key string
+
+const liveQueryCtx key = "live_query"
+
+func (l LiveQuery) ExtensionName() string {
+	return "LiveQuery"
+}
+
+func (l *LiveQuery) Validate(schema graphql.ExecutableSchema) error {
+	if !declaresLive(schema.Schema()) {
+		return fmt.Errorf("LiveQuery needs the schema to declare directive @live on QUERY")
+	}
+	if l.Broker == nil {
+		// an invalidation waiting for a live query covers the ones published after it
+		l.Broker = pubsub.New(1, pubsub.OverflowDropMessage)
+	}
+	return nil
+}
+
+func (l *LiveQuery) throttle() time.Duration {
+	if l.Throttle == 0 {
+		return time.Second
+	}
+	return l.Throttle
+}
+
+// Invalidate executes the live queries depending on one of topics again.
+func (l *LiveQuery) Invalidate(ctx context.Context, topics ...string) error {
+	// no live query can be running before the extension is used
+	if l.Broker == nil {
+		return nil
+	}
+	for _, topic := range topics {
+		if err := l.Broker.Publish(ctx, liveTopic(topic), struct{}{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LiveQuery) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	rc := graphql.GetOperationContext(ctx)
+	if rc.Operation.Operation != ast.Query || rc.Operation.Directives.ForName("live") == nil || !graphql.IsStreaming(ctx) {
+		return next(ctx)
+	}
+
+	q := &liveQuery{
+		ext:           l,
+		next:          next,
+		invalidated:   make(chan struct{}, 1),
+		subscriptions: map[string]context.CancelFunc{},
+	}
+	q.ctx, q.cancel = context.WithCancel(ctx)
+	q.dispatch()
+
+	return q.respond
+}
+
+// RegisterLiveTopics registers the topics the data returned by the resolver depends on, the @live query being
+// executed is executed again once one of them is invalidated. Register them before reading the data, so the changes
+// made in the meantime aren't missed. It does nothing outside of live queries.
+func RegisterLiveTopics(ctx context.Context, topics ...string) {
+	q, ok := ctx.Value(liveQueryCtx).(*liveQuery)
+	if !ok {
+		return
+	}
+	for _, topic := range topics {
+		q.register(topic)
+	}
+}
+
+// liveQuery is a query marked with @live, served until its context is done.
+type liveQuery struct {
+	ext  *LiveQuery
+	next graphql.OperationHandler
+
+	// ctx ends the subscriptions to the topics once the query is over
+	ctx         context.Context
+	cancel      context.CancelFunc
+	invalidated chan struct{}
+
+	// current is the execution the transport is yet to read, pending the changed results it isn't done sending
+	current  graphql.ResponseHandler
+	executed time.Time
+	pending  []*graphql.Response
+	last     []byte
+	done     bool
+
+	mu            sync.Mutex
+	registered    map[string]struct{}
+	subscriptions map[string]context.CancelFunc
+	err           error
+}
+
+func (q *liveQuery) respond(ctx context.Context) *graphql.Response {
+	for len(q.pending) == 0 {
+		if q.done {
+			q.cancel()
+			return nil
+		}
+		if q.current == nil {
+			if !q.wait(ctx) {
+				q.cancel()
+				return nil
+			}
+			q.dispatch()
+		}
+		q.collect(ctx)
+	}
+
+	resp := q.pending[0]
+	q.pending = q.pending[1:]
+	return resp
+}
+
+// dispatch starts an execution of the query, the topics registered by the previous one are forgotten.
+func (q *liveQuery) dispatch() {
+	q.mu.Lock()
+	q.registered = map[string]struct{}{}
+	q.mu.Unlock()
+
+	q.executed = graphql.Now()
+	q.current = q.next(context.WithValue(q.ctx, liveQueryCtx, q))
+}
+
+// collect reads the results of the current execution, they are only sent when they changed.
+func (q *liveQuery) collect(ctx context.Context) {
+	ctx = context.WithValue(ctx, liveQueryCtx, q)
+
+	var results []*graphql.Response
+	for resp := q.current(ctx); resp != nil; resp = q.current(ctx) {
+		results = append(results, resp)
+	}
+	q.current = nil
+
+	if err := q.unsubscribeUnregistered(); err != nil {
+		// the query can't tell when its data changes anymore
+		q.done = true
+		if len(results) == 0 {
+			results = append(results, &graphql.Response{})
+		}
+		last := results[len(results)-1]
+		last.Errors = append(last.Errors, &gqlerror.Error{Message: "live query stopped: " + err.Error()})
+	}
+
+	if fp := fingerprint(results); q.done || !bytes.Equal(fp, q.last) {
+		q.last = fp
+		q.pending = results
+	}
+}
+
+// wait returns once the query has been invalidated and throttled, or false once it is over.
+func (q *liveQuery) wait(ctx context.Context) bool {
+	shutdown := graphql.ShuttingDown(q.ctx)
+
+	select {
+	case <-q.invalidated:
+	case <-q.ctx.Done():
+		return false
+	case <-ctx.Done():
+		return false
+	case <-shutdown:
+		return false
+	}
+
+	if wait := q.executed.Add(q.ext.throttle()).Sub(graphql.Now()); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-q.ctx.Done():
+			return false
+		case <-ctx.Done():
+			return false
+		case <-shutdown:
+			return false
+		}
+	}
+
+	// the invalidations received while throttled are handled by the coming execution
+	select {
+	case <-q.invalidated:
+	default:
+	}
+	return true
+}
+
+func (q *liveQuery) register(topic string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.registered[topic] = struct{}{}
+	if _, ok := q.subscriptions[topic]; ok || q.err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(q.ctx)
+	sub, err := q.ext.Broker.Subscribe(ctx, liveTopic(topic))
+	if err != nil {
+		cancel()
+		q.err = err
+		return
+	}
+	q.subscriptions[topic] = cancel
+
+	go func() {
+		for range sub {
+			select {
+			case q.invalidated <- struct{}{}:
+			default:
+			}
+		}
+	}()
+}
+
+// unsubscribeUnregistered ends the subscriptions to the topics the last execution didn't register, and returns the
+// error of the subscriptions that failed.
+func (q *liveQuery) unsubscribeUnregistered() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for topic, cancel := range q.subscriptions {
+		if _, ok := q.registered[topic]; !ok {
+			cancel()
+			delete(q.subscriptions, topic)
+		}
+	}
+	return q.err
+}
+
+// fingerprint identifies the results of an execution. Extensions are left out, tracing for instance sets them on
+// every execution.
+func fingerprint(results []*graphql.Response) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, resp := range results {
+		resp := *resp
+		resp.Extensions = nil
+		_ = enc.Encode(&resp)
+	}
+	return buf.Bytes()
+}
+
+// liveTopic keeps the invalidations apart from the other messages of a shared broker.
+func liveTopic(topic string) string {
+	return "live:" + topic
+}
+
+func declaresLive(schema *ast.Schema) bool {
+	d := schema.Directives["live"]
+	if d == nil {
+		return false
+	}
+	for _, loc := range d.Locations {
+		if loc == ast.LocationQuery {
+			return true
+		}
+	}
+	return false
+}
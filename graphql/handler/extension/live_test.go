@@ -0,0 +1,155 @@
+package extension_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/executor"
+	"github.com/sujamess/fastgql/graphql/handler/extension"
+	"github.com/sujamess/fastgql/graphql/pubsub"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestLiveQuery(t *testing.T) {
+	var count int32
+	hub := pubsub.New(1, pubsub.OverflowDropMessage)
+	exec := executor.New(liveSchema(&count))
+	live := &extension.LiveQuery{Broker: hub, Throttle: 50 * time.Millisecond}
+	exec.Use(live)
+
+	run := func(ctx context.Context, query string) <-chan *graphql.Response {
+		var rctx fasthttp.RequestCtx
+		graphql.StartOperationTrace(&rctx)
+		rc, err := exec.CreateOperationContext(&rctx, &graphql.RawParams{Query: query})
+		require.Nil(t, err)
+		responses, ctx := exec.DispatchOperation(ctx, rc)
+
+		ch := make(chan *graphql.Response)
+		go func() {
+			defer close(ch)
+			for resp := responses(ctx); resp != nil; resp = responses(ctx) {
+				ch <- resp
+			}
+		}()
+		return ch
+	}
+
+	t.Run("results are sent again once they changed", func(t *testing.T) {
+		atomic.StoreInt32(&count, 1)
+		ctx, cancel := context.WithCancel(graphql.WithStreaming(context.Background()))
+		defer cancel()
+
+		responses := run(ctx, "query @live { count }")
+		assert.Equal(t, `{"count":1}`, string((<-responses).Data))
+
+		require.NoError(t, live.Invalidate(ctx, "count"))
+		select {
+		case resp := <-responses:
+			t.Fatalf("unchanged result sent: %s", resp.Data)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		atomic.StoreInt32(&count, 2)
+		require.NoError(t, live.Invalidate(ctx, "count"))
+		assert.Equal(t, `{"count":2}`, string((<-responses).Data))
+	})
+
+	t.Run("executions are throttled", func(t *testing.T) {
+		atomic.StoreInt32(&count, 1)
+		ctx, cancel := context.WithCancel(graphql.WithStreaming(context.Background()))
+		defer cancel()
+
+		responses := run(ctx, "query @live { count }")
+		<-responses
+		start := time.Now()
+
+		atomic.StoreInt32(&count, 2)
+		require.NoError(t, live.Invalidate(ctx, "count"))
+		assert.Equal(t, `{"count":2}`, string((<-responses).Data))
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(40*time.Millisecond))
+	})
+
+	t.Run("the query ends with its context", func(t *testing.T) {
+		// the queries of the previous tests unsubscribe in the background
+		require.Eventually(t, func() bool {
+			return hub.Subscribers("live:count") == 0
+		}, time.Second, 10*time.Millisecond)
+		ctx, cancel := context.WithCancel(graphql.WithStreaming(context.Background()))
+
+		responses := run(ctx, "query @live { count }")
+		<-responses
+		assert.Equal(t, 1, hub.Subscribers("live:count"))
+
+		cancel()
+		_, ok := <-responses
+		assert.False(t, ok)
+		assert.Eventually(t, func() bool {
+			return hub.Subscribers("live:count") == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("executed once without a streaming transport", func(t *testing.T) {
+		atomic.StoreInt32(&count, 1)
+
+		var results []string
+		for resp := range run(context.Background(), "query @live { count }") {
+			results = append(results, string(resp.Data))
+		}
+		assert.Equal(t, []string{`{"count":1}`}, results)
+		assert.Equal(t, 0, hub.Subscribers("live:count"))
+	})
+
+	t.Run("queries without @live are executed once", func(t *testing.T) {
+		atomic.StoreInt32(&count, 1)
+
+		var results []string
+		for resp := range run(graphql.WithStreaming(context.Background()), "{ count }") {
+			results = append(results, string(resp.Data))
+		}
+		assert.Equal(t, []string{`{"count":1}`}, results)
+	})
+
+	t.Run("the schema must declare the directive", func(t *testing.T) {
+		exec := executor.New(&graphql.ExecutableSchemaMock{
+			SchemaFunc: func() *ast.Schema {
+				return gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { count: Int! }`})
+			},
+		})
+		assert.Panics(t, func() {
+			exec.Use(&extension.LiveQuery{})
+		})
+	})
+}
+
+// liveSchema serves a count field depending on the count topic.
+func liveSchema(count *int32) graphql.ExecutableSchema {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		directive @live on QUERY
+		type Query { count: Int! }
+	`})
+
+	return &graphql.ExecutableSchemaMock{
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			ran := false
+			return func(ctx context.Context) *graphql.Response {
+				if ran {
+					return nil
+				}
+				ran = true
+				extension.RegisterLiveTopics(ctx, "count")
+				return &graphql.Response{Data: []byte(fmt.Sprintf(`{"count":%d}`, atomic.LoadInt32(count)))}
+			}
+		},
+		SchemaFunc: func() *ast.Schema {
+			return schema
+		},
+	}
+}
@@ -0,0 +1,260 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+const errOperationNotPersisted = "OPERATION_NOT_PERSISTED"
+
+func init() {
+	errcode.RegisterErrorType(errOperationNotPersisted, errcode.KindProtocol)
+}
+
+// PersistedOperations serves the operations of a manifest built with the clients, also known as trusted documents.
+// Unlike AutomaticPersistedQuery, clients can't add operations, so it can be used to only serve the operations of
+// the manifest.
+//
+// Both manifests generated by Apollo, with the apollo-persisted-query-manifest format, and the ones generated by
+// Relay, mapping ids to documents, are supported. Apollo clients send the id of an operation in the persistedQuery
+// extension, the same way they do for AutomaticPersistedQuery, and Relay clients in the doc_id or id parameter.
+//
+// The operations are parsed and validated once, when the manifest is read, and kept by the extension for as long as
+// they are in the manifest.
+type PersistedOperations struct {
+	// Manifest returns the manifest, it is read when the extension is added to the server and on Reload.
+	Manifest func() ([]byte, error)
+
+	// Enforce rejects the operations that aren't in the manifest.
+	Enforce bool
+
+	mu         sync.RWMutex
+	schema     *ast.Schema
+	operations map[string]*persistedOperation
+	queries    map[string]*persistedOperation
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = &PersistedOperations{}
+
+const persistedOperationsExtension = "PersistedOperations"
+
+type PersistedOperationStats struct {
+	// The id of the operation in the manifest
+	ID string
+
+	// The name of the operation in the manifest
+	Name string
+
+	// SentQuery is true if the request sent the query instead of the id
+	SentQuery bool
+}
+
+type persistedOperation struct {
+	id    string
+	name  string
+	query string
+	doc   *ast.QueryDocument
+}
+
+// apolloManifest is the manifest generated by the Apollo tooling
+// see https://www.apollographql.com/docs/graphos/operations/persisted-queries
+type apolloManifest struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Operations []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Body string `json:"body"`
+	} `json:"operations"`
+}
+
+const apolloManifestFormat = "apollo-persisted-query-manifest"
+
+// PersistedOperationsFile reads the manifest of PersistedOperations from a file.
+func PersistedOperationsFile(filename string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return ioutil.ReadFile(filename)
+	}
+}
+
+func (p *PersistedOperations) ExtensionName() string {
+	return persistedOperationsExtension
+}
+
+func (p *PersistedOperations) Validate(schema graphql.ExecutableSchema) error {
+	if p.Manifest == nil {
+		return fmt.Errorf("PersistedOperations.Manifest can not be nil")
+	}
+
+	p.mu.Lock()
+	p.schema = schema.Schema()
+	p.mu.Unlock()
+
+	return p.Reload()
+}
+
+// Reload reads the manifest again, the operations it holds replace the ones served so far once they have all been
+// validated. The operations served so far are kept when the manifest is invalid.
+func (p *PersistedOperations) Reload() error {
+	p.mu.RLock()
+	schema := p.schema
+	p.mu.RUnlock()
+	if schema == nil {
+		return fmt.Errorf("PersistedOperations must be added to a server before being reloaded")
+	}
+
+	b, err := p.Manifest()
+	if err != nil {
+		return fmt.Errorf("unable to read the persisted operations manifest: %w", err)
+	}
+	operations, err := parsePersistedManifest(b)
+	if err != nil {
+		return err
+	}
+
+	queries := make(map[string]*persistedOperation, len(operations))
+	for _, op := range operations {
+		doc, err := parser.ParseQuery(&ast.Source{Input: op.query})
+		if err != nil {
+			return fmt.Errorf("persisted operation %s could not be parsed: %w", op.id, err)
+		}
+		if errs := validator.Validate(schema, doc); len(errs) != 0 {
+			return fmt.Errorf("persisted operation %s is invalid: %s", op.id, strings.TrimSpace(errs.Error()))
+		}
+		op.doc = doc
+		if op.name == "" && len(doc.Operations) > 0 {
+			op.name = doc.Operations[0].Name
+		}
+		queries[op.query] = op
+	}
+
+	p.mu.Lock()
+	p.operations = operations
+	p.queries = queries
+	p.mu.Unlock()
+
+	return nil
+}
+
+func parsePersistedManifest(b []byte) (map[string]*persistedOperation, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("persisted operations manifest could not be decoded: %w", err)
+	}
+
+	operations := map[string]*persistedOperation{}
+
+	if _, ok := entries["format"]; ok {
+		var manifest apolloManifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, fmt.Errorf("persisted operations manifest could not be decoded: %w", err)
+		}
+		if manifest.Format != apolloManifestFormat || manifest.Version != 1 {
+			return nil, fmt.Errorf("unsupported persisted operations manifest %s version %d", manifest.Format, manifest.Version)
+		}
+		for _, op := range manifest.Operations {
+			operations[op.ID] = &persistedOperation{id: op.ID, name: op.Name, query: op.Body}
+		}
+		return operations, nil
+	}
+
+	for id, entry := range entries {
+		var query string
+		if err := json.Unmarshal(entry, &query); err != nil {
+			return nil, fmt.Errorf("persisted operation %s must be a document", id)
+		}
+		operations[id] = &persistedOperation{id: id, query: query}
+	}
+	return operations, nil
+}
+
+func (p *PersistedOperations) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	p.mu.RLock()
+	operations, queries := p.operations, p.queries
+	p.mu.RUnlock()
+
+	var op *persistedOperation
+	sentQuery := rawParams.Query != ""
+
+	id, apq := rawParams.DocumentID, false
+	if id == "" {
+		id = rawParams.ID
+	}
+	if rawParams.Extensions["persistedQuery"] != nil {
+		var extension struct {
+			Sha256 string `mapstructure:"sha256Hash"`
+		}
+		if err := mapstructure.Decode(rawParams.Extensions["persistedQuery"], &extension); err != nil {
+			err := gqlerror.Errorf("invalid APQ extension data")
+			errcode.Set(err, errcode.ValidationFailed)
+			return err
+		}
+		id, apq = extension.Sha256, true
+	}
+
+	if id != "" {
+		op = operations[id]
+		if op == nil && !sentQuery {
+			if apq && !p.Enforce {
+				// the operation may be registered by AutomaticPersistedQuery
+				return nil
+			}
+			err := gqlerror.Errorf(errPersistedQueryNotFound)
+			errcode.Set(err, errPersistedQueryNotFoundCode)
+			return err
+		}
+		if op != nil && sentQuery && rawParams.Query != op.query {
+			err := gqlerror.Errorf("provided APQ hash does not match query")
+			errcode.Set(err, errcode.ValidationFailed)
+			return err
+		}
+	}
+
+	if op == nil {
+		op = queries[rawParams.Query]
+	}
+	if op == nil {
+		if p.Enforce {
+			err := gqlerror.Errorf("operation is not in the persisted operations")
+			errcode.Set(err, errOperationNotPersisted)
+			return err
+		}
+		return nil
+	}
+
+	rawParams.Query = op.query
+	rawParams.Doc = op.doc
+
+	graphql.GetOperationContext(ctx).Stats.SetExtension(persistedOperationsExtension, &PersistedOperationStats{
+		ID:        op.id,
+		Name:      op.name,
+		SentQuery: sentQuery,
+	})
+
+	return nil
+}
+
+func GetPersistedOperationStats(ctx context.Context) *PersistedOperationStats {
+	rc := graphql.GetOperationContext(ctx)
+	if rc == nil {
+		return nil
+	}
+
+	s, _ := rc.Stats.GetExtension(persistedOperationsExtension).(*PersistedOperationStats)
+	return s
+}
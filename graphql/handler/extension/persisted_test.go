@@ -0,0 +1,133 @@
+package extension_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/extension"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+const apolloManifest = `{
+	"format": "apollo-persisted-query-manifest",
+	"version": 1,
+	"operations": [{"id": "30166fc3298853f22709fce1e4a00e98f1b6a3160eaaaf9cb3b7db6a16073b07", "name": "Name", "type": "query", "body": "query Name { name }"}]
+}`
+
+func TestPersistedOperations(t *testing.T) {
+	manifest := apolloManifest
+	cache := graphql.MapCache{}
+	persisted := &extension.PersistedOperations{
+		Manifest: func() ([]byte, error) { return []byte(manifest), nil },
+		Enforce:  true,
+	}
+
+	h := testserver.New()
+	h.Use(persisted)
+	h.AddTransport(&transport.GET{})
+	h.AddTransport(&transport.POST{})
+	h.SetQueryCache(cache)
+
+	var stats *extension.PersistedOperationStats
+	h.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		stats = extension.GetPersistedOperationStats(ctx)
+		return next(ctx)
+	})
+
+	t.Run("operation sent by id", func(t *testing.T) {
+		stats = nil
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"30166fc3298853f22709fce1e4a00e98f1b6a3160eaaaf9cb3b7db6a16073b07"}}}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+
+		require.Equal(t, &extension.PersistedOperationStats{
+			ID:   "30166fc3298853f22709fce1e4a00e98f1b6a3160eaaaf9cb3b7db6a16073b07",
+			Name: "Name",
+		}, stats)
+	})
+
+	t.Run("operations are not parsed again", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"query Name { name }"}`)
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+
+		_, ok := cache.Get(context.Background(), "query Name { name }")
+		require.False(t, ok, "the query cache is left to the other operations")
+	})
+
+	t.Run("operation sent by query", func(t *testing.T) {
+		stats = nil
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"query Name { name }"}`)
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+
+		require.Equal(t, "Name", stats.Name)
+		require.True(t, stats.SentQuery)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"unknown"}}}`)
+		require.Equal(t, `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("query not matching the id", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ name }","extensions":{"persistedQuery":{"version":1,"sha256Hash":"30166fc3298853f22709fce1e4a00e98f1b6a3160eaaaf9cb3b7db6a16073b07"}}}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"provided APQ hash does not match query","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("operation not in the manifest", func(t *testing.T) {
+		stats = nil
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ name }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"operation is not in the persisted operations","extensions":{"code":"OPERATION_NOT_PERSISTED"}}],"data":null}`, string(resp.Body()))
+		require.Nil(t, stats)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		manifest = `{"relay-1": "query Find { find(id: 1) }"}`
+		require.NoError(t, persisted.Reload())
+
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"doc_id":"relay-1"}`)
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		require.Equal(t, "relay-1", stats.ID)
+		require.Equal(t, "Find", stats.Name)
+
+		stats = nil
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"id":"relay-1"}`)
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		require.Equal(t, "relay-1", stats.ID)
+
+		stats = nil
+		resp = doRequest(h.Handler(), "GET", "/graphql?doc_id=relay-1", "")
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		require.Equal(t, "relay-1", stats.ID)
+
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"doc_id":"relay-2"}`)
+		require.Equal(t, `{"errors":[{"message":"PersistedQueryNotFound","extensions":{"code":"PERSISTED_QUERY_NOT_FOUND"}}],"data":null}`, string(resp.Body()))
+
+		resp = doRequest(h.Handler(), "POST", "/graphql", `{"query":"query Name { name }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+	})
+
+	t.Run("invalid manifests are not loaded", func(t *testing.T) {
+		manifest = `{"relay-2": "{ unknown }"}`
+		require.EqualError(t, persisted.Reload(), `persisted operation relay-2 is invalid: input:1: Cannot query field "unknown" on type "Query".`)
+
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"doc_id":"relay-1"}`)
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+}
+
+func TestPersistedOperationsNotEnforced(t *testing.T) {
+	h := testserver.New()
+	h.Use(&extension.PersistedOperations{
+		Manifest: func() ([]byte, error) { return []byte(apolloManifest), nil },
+	})
+	h.AddTransport(&transport.POST{})
+
+	resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ name }"}`)
+	require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+}
@@ -0,0 +1,195 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+const (
+	errQueryTooLarge     = "QUERY_TOO_LARGE"
+	errTooManyAliases    = "TOO_MANY_ALIASES"
+	errTooManyRootFields = "TOO_MANY_ROOT_FIELDS"
+	errTooManyDirectives = "TOO_MANY_DIRECTIVES"
+	errTooManyTokens     = "TOO_MANY_TOKENS"
+)
+
+func init() {
+	for _, code := range []string{errQueryTooLarge, errTooManyAliases, errTooManyRootFields, errTooManyDirectives, errTooManyTokens} {
+		errcode.RegisterErrorType(code, errcode.KindProtocol)
+	}
+}
+
+// QueryLimits rejects documents whose shape can be abused without raising their complexity, like the ones aliasing a
+// field many times or stacking directives. The limits left at 0 aren't enforced.
+//
+// Fields are counted as they are written, the ones of a fragment are counted every time it is spread.
+type QueryLimits struct {
+	// MaxQuerySize sets the maximum size of the query in bytes, it is checked before the query is parsed.
+	MaxQuerySize int
+
+	// MaxAliases sets the maximum number of aliased fields in an operation.
+	MaxAliases int
+
+	// MaxRootFields sets the maximum number of fields selected at the root of an operation.
+	MaxRootFields int
+
+	// MaxDirectives sets the maximum number of directives on a single field or fragment.
+	MaxDirectives int
+
+	// MaxTokens sets the maximum number of tokens in the query, comments left out.
+	MaxTokens int
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = QueryLimits{}
+
+func (q QueryLimits) ExtensionName() string {
+	return "QueryLimits"
+}
+
+func (q QueryLimits) Validate(schema graphql.ExecutableSchema) error {
+	if q.MaxQuerySize < 0 || q.MaxAliases < 0 || q.MaxRootFields < 0 || q.MaxDirectives < 0 || q.MaxTokens < 0 {
+		return fmt.Errorf("QueryLimits can not be negative")
+	}
+	if q == (QueryLimits{}) {
+		return fmt.Errorf("QueryLimits needs at least one limit")
+	}
+	return nil
+}
+
+func (q QueryLimits) MutateOperationParameters(ctx context.Context, request *graphql.RawParams) *gqlerror.Error {
+	if q.MaxQuerySize > 0 && len(request.Query) > q.MaxQuerySize {
+		err := gqlerror.Errorf("query is %d bytes, which exceeds the limit of %d", len(request.Query), q.MaxQuerySize)
+		errcode.Set(err, errQueryTooLarge)
+		return err
+	}
+	return nil
+}
+
+func (q QueryLimits) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if q.MaxTokens > 0 {
+		// the query has been parsed, it can't hold invalid tokens
+		if tokens := countTokens(rc.RawQuery, q.MaxTokens); tokens > q.MaxTokens {
+			err := gqlerror.Errorf("query has more than %d tokens", q.MaxTokens)
+			errcode.Set(err, errTooManyTokens)
+			return err
+		}
+	}
+
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	walker := shapeWalker{fragments: map[string]selectionShape{}}
+	shape := walker.selectionSetShape(op.SelectionSet)
+
+	if q.MaxRootFields > 0 && shape.fields > q.MaxRootFields {
+		err := gqlerror.Errorf("operation has %d root fields, which exceeds the limit of %d", shape.fields, q.MaxRootFields)
+		errcode.Set(err, errTooManyRootFields)
+		return err
+	}
+	if q.MaxAliases > 0 && shape.aliases > q.MaxAliases {
+		err := gqlerror.Errorf("operation has %d aliases, which exceeds the limit of %d", shape.aliases, q.MaxAliases)
+		errcode.Set(err, errTooManyAliases)
+		return err
+	}
+	if q.MaxDirectives > 0 && shape.directives > q.MaxDirectives {
+		err := gqlerror.Errorf("operation has a selection with %d directives, which exceeds the limit of %d", shape.directives, q.MaxDirectives)
+		errcode.Set(err, errTooManyDirectives)
+		return err
+	}
+
+	return nil
+}
+
+// countTokens counts the tokens of query, it stops counting once there are more than limit.
+func countTokens(query string, limit int) int {
+	l := lexer.New(&ast.Source{Input: query})
+	tokens := 0
+	for tokens <= limit {
+		token, err := l.ReadToken()
+		if err != nil || token.Kind == lexer.EOF {
+			break
+		}
+		if token.Kind != lexer.Comment {
+			tokens++
+		}
+	}
+	return tokens
+}
+
+// selectionShape measures a selection set. fields are the fields it selects itself, aliases the aliased fields at
+// any depth, and directives the most directives found on a single selection.
+type selectionShape struct {
+	fields     int
+	aliases    int
+	directives int
+}
+
+// shapeWalker measures the shape of selection sets, the shape of a fragment is only measured once however many times
+// it is spread.
+type shapeWalker struct {
+	fragments map[string]selectionShape
+}
+
+func (sw shapeWalker) selectionSetShape(selectionSet ast.SelectionSet) selectionShape {
+	var shape selectionShape
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			shape.fields = saturatingAdd(shape.fields, 1)
+			if s.Alias != s.Name {
+				shape.aliases = saturatingAdd(shape.aliases, 1)
+			}
+			shape.directives = max(shape.directives, len(s.Directives))
+
+			child := sw.selectionSetShape(s.SelectionSet)
+			shape.aliases = saturatingAdd(shape.aliases, child.aliases)
+			shape.directives = max(shape.directives, child.directives)
+
+		case *ast.FragmentSpread:
+			shape.directives = max(shape.directives, len(s.Directives))
+			if s.Definition == nil {
+				continue
+			}
+			fragment, ok := sw.fragments[s.Name]
+			if !ok {
+				// validation rejects fragment cycles, the marker only guards against unvalidated documents
+				sw.fragments[s.Name] = selectionShape{}
+				fragment = sw.selectionSetShape(s.Definition.SelectionSet)
+				sw.fragments[s.Name] = fragment
+			}
+			shape = shape.merge(fragment)
+
+		case *ast.InlineFragment:
+			shape.directives = max(shape.directives, len(s.Directives))
+			shape = shape.merge(sw.selectionSetShape(s.SelectionSet))
+		}
+	}
+	return shape
+}
+
+// merge adds the shape of a fragment to the shape of the selection set it is spread in.
+func (s selectionShape) merge(fragment selectionShape) selectionShape {
+	return selectionShape{
+		fields:     saturatingAdd(s.fields, fragment.fields),
+		aliases:    saturatingAdd(s.aliases, fragment.aliases),
+		directives: max(s.directives, fragment.directives),
+	}
+}
+
+// saturatingAdd adds a and b, returning the maximum integer instead of overflowing. Fragments spread many times can
+// make the counts grow exponentially with the size of the document.
+func saturatingAdd(a, b int) int {
+	c := a + b
+	if c < a {
+		return int(^uint(0) >> 1)
+	}
+	return c
+}
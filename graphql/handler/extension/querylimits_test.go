@@ -0,0 +1,61 @@
+package extension_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql/handler/extension"
+	"github.com/valyala/fasthttp"
+)
+
+func TestQueryLimits(t *testing.T) {
+	h := nestedServer(extension.QueryLimits{
+		MaxQuerySize:  300,
+		MaxAliases:    2,
+		MaxRootFields: 2,
+		MaxDirectives: 1,
+		MaxTokens:     40,
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ a: user { name } b: user { name @skip(if: false) } }"}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"data":{}}`, string(resp.Body()))
+	})
+
+	t.Run("query too large", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { name } }`+strings.Repeat(" ", 300)+`"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"query is 317 bytes, which exceeds the limit of 300","extensions":{"code":"QUERY_TOO_LARGE"}}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("too many tokens", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { `+strings.Repeat("name ", 40)+`} }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"query has more than 40 tokens","extensions":{"code":"TOO_MANY_TOKENS"}}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("comments are not tokens", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"`+strings.Repeat(`# a comment\n`, 10)+`{ user { name } }"}`)
+		require.Equal(t, `{"data":{}}`, string(resp.Body()))
+	})
+
+	t.Run("too many root fields", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { name } ...Root } fragment Root on Query { user { name } user { name } }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"operation has 3 root fields, which exceeds the limit of 2","extensions":{"code":"TOO_MANY_ROOT_FIELDS"}}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("too many aliases", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { ...Names friends { ...Names } } } fragment Names on User { a: name b: name }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"operation has 4 aliases, which exceeds the limit of 2","extensions":{"code":"TOO_MANY_ALIASES"}}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("too many directives", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ user { ... on User @skip(if: false) @include(if: true) { name } } }"}`)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"operation has a selection with 2 directives, which exceeds the limit of 1","extensions":{"code":"TOO_MANY_DIRECTIVES"}}],"data":null}`, string(resp.Body()))
+	})
+}
@@ -0,0 +1,271 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sujamess/fastgql/complexity"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/errcode"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errRateLimited          = "RATE_LIMITED"
+	errRateLimitUnavailable = "RATE_LIMIT_UNAVAILABLE"
+)
+
+func init() {
+	errcode.RegisterErrorType(errRateLimited, errcode.KindRateLimited)
+	errcode.RegisterErrorType(errRateLimitUnavailable, errcode.KindProtocol)
+}
+
+// ComplexityRateLimit charges the complexity of operations against a budget per client, so clients sending many
+// operations that each fit in the ComplexityLimit still run out of budget.
+//
+// Every client has a bucket holding up to Budget, which refills entirely over Window. Operations costing more than is
+// left in the bucket are rejected with a 429 status code and a Retry-After header. The state of the bucket is sent in
+// the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers and in the rateLimit extension of the response.
+// The headers of a batch carry the state of its tightest budget, and websocket operations only get the extension.
+type ComplexityRateLimit struct {
+	// Key returns the client an operation is charged to, like its API key, user ID or IP. Operations with an empty
+	// key aren't limited.
+	Key func(ctx context.Context, rc *graphql.OperationContext) string
+
+	// Budget sets the complexity a client can spend at once.
+	Budget int
+
+	// Window sets how long an empty bucket takes to refill.
+	Window time.Duration
+
+	// Store keeps the buckets of the clients. Defaults to a MemoryRateLimitStore, use a shared store when the
+	// server runs on several instances.
+	Store RateLimitStore
+
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.ResponseInterceptor
+	graphql.HandlerExtension
+} = &ComplexityRateLimit{}
+
+const rateLimitExtension = "ComplexityRateLimit"
+
+// RateLimitStore keeps the complexity budget of the clients of a ComplexityRateLimit.
+type RateLimitStore interface {
+	// Take charges cost to the bucket of key, which holds up to budget and refills entirely over window. Nothing is
+	// charged when the bucket doesn't hold enough.
+	Take(ctx context.Context, key string, cost, budget int, window time.Duration) (RateLimitResult, error)
+}
+
+// RateLimitResult is the state of a bucket after it has been charged.
+type RateLimitResult struct {
+	// Allowed is true when the bucket held enough for the charge
+	Allowed bool
+
+	// Remaining is what is left in the bucket
+	Remaining int
+
+	// Reset is how long the bucket takes to be full again
+	Reset time.Duration
+
+	// RetryAfter is how long the bucket takes to hold enough for the charge that wasn't allowed, it is 0 when the
+	// charge exceeds the budget
+	RetryAfter time.Duration
+}
+
+type RateLimitStats struct {
+	// The client the operation was charged to
+	Key string
+
+	// The complexity charged for the operation
+	Complexity int
+
+	// The budget of the client
+	Budget int
+
+	RateLimitResult
+}
+
+// rateLimitResponse is the rateLimit extension of the responses.
+type rateLimitResponse struct {
+	Cost      int `json:"cost"`
+	Budget    int `json:"budget"`
+	Remaining int `json:"remaining"`
+	// ResetAfter is the number of seconds until the budget is full again
+	ResetAfter int `json:"resetAfter"`
+}
+
+// RemoteIPKey charges the operations to the IP of the client, it is a Key for ComplexityRateLimit.
+func RemoteIPKey(ctx context.Context, rc *graphql.OperationContext) string {
+	if info := graphql.GetRequestInfo(ctx); info != nil {
+		return info.RemoteIP.String()
+	}
+	return ""
+}
+
+func (r ComplexityRateLimit) ExtensionName() string {
+	return rateLimitExtension
+}
+
+func (r *ComplexityRateLimit) Validate(schema graphql.ExecutableSchema) error {
+	if r.Key == nil {
+		return fmt.Errorf("ComplexityRateLimit key can not be nil")
+	}
+	if r.Budget <= 0 || r.Window <= 0 {
+		return fmt.Errorf("ComplexityRateLimit budget and window must be positive")
+	}
+	if r.Store == nil {
+		r.Store = NewMemoryRateLimitStore()
+	}
+	r.es = schema
+	return nil
+}
+
+func (r ComplexityRateLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	key := r.Key(ctx, rc)
+	if key == "" {
+		return nil
+	}
+
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	cost := complexity.Calculate(r.es, op, rc.Variables)
+
+	result, err := r.Store.Take(ctx, key, cost, r.Budget, r.Window)
+	if err != nil {
+		gerr := gqlerror.Errorf("rate limit could not be checked: %s", err.Error())
+		errcode.Set(gerr, errRateLimitUnavailable)
+		return gerr
+	}
+
+	rc.Stats.SetExtension(rateLimitExtension, &RateLimitStats{
+		Key:             key,
+		Complexity:      cost,
+		Budget:          r.Budget,
+		RateLimitResult: result,
+	})
+
+	graphql.UpdateRequestCtx(ctx, func(rctx *fasthttp.RequestCtx) {
+		// the operations of a batch share the response, which gets the state of the tightest budget
+		if remaining, err := strconv.Atoi(string(rctx.Response.Header.Peek("RateLimit-Remaining"))); err != nil || result.Remaining < remaining {
+			rctx.Response.Header.Set("RateLimit-Limit", strconv.Itoa(r.Budget))
+			rctx.Response.Header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			rctx.Response.Header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		}
+		if !result.Allowed && result.RetryAfter > 0 {
+			retryAfter, err := strconv.Atoi(string(rctx.Response.Header.Peek("Retry-After")))
+			if err != nil || seconds(result.RetryAfter) > retryAfter {
+				rctx.Response.Header.Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			}
+		}
+	})
+
+	if !result.Allowed {
+		err := gqlerror.Errorf("operation has complexity %d, which exceeds the remaining rate limit budget of %d", cost, result.Remaining)
+		errcode.Set(err, errRateLimited)
+		return err
+	}
+
+	return nil
+}
+
+func (r ComplexityRateLimit) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if stats := GetRateLimitStats(ctx); stats != nil {
+		graphql.RegisterExtension(ctx, "rateLimit", &rateLimitResponse{
+			Cost:       stats.Complexity,
+			Budget:     stats.Budget,
+			Remaining:  stats.Remaining,
+			ResetAfter: seconds(stats.Reset),
+		})
+	}
+	return next(ctx)
+}
+
+func GetRateLimitStats(ctx context.Context) *RateLimitStats {
+	rc := graphql.GetOperationContext(ctx)
+	if rc == nil {
+		return nil
+	}
+
+	s, _ := rc.Stats.GetExtension(rateLimitExtension).(*RateLimitStats)
+	return s
+}
+
+// seconds rounds d up to whole seconds, as the rate limit headers don't carry fractions.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// MemoryRateLimitStore keeps the buckets of a single server in memory.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket is the budget left to a client when it was last charged.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	window  time.Duration
+}
+
+var _ RateLimitStore = &MemoryRateLimitStore{}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: map[string]*tokenBucket{}}
+}
+
+func (m *MemoryRateLimitStore) Take(ctx context.Context, key string, cost, budget int, window time.Duration) (RateLimitResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := graphql.Now()
+	m.sweep(now, window)
+
+	// tokens are refilled at budget per window
+	rate := float64(budget) / float64(window)
+
+	bucket, ok := m.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(budget)}
+		m.buckets[key] = bucket
+	} else {
+		bucket.tokens = math.Min(float64(budget), bucket.tokens+float64(now.Sub(bucket.updated))*rate)
+	}
+	bucket.updated = now
+	bucket.window = window
+
+	result := RateLimitResult{Allowed: float64(cost) <= bucket.tokens}
+	if result.Allowed {
+		bucket.tokens -= float64(cost)
+	} else if cost <= budget {
+		result.RetryAfter = time.Duration((float64(cost) - bucket.tokens) / rate)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = time.Duration((float64(budget) - bucket.tokens) / rate)
+
+	return result, nil
+}
+
+// sweep forgets the buckets that are full again, as they are the same as new ones. It runs at most once per window
+// so it doesn't cost more than the charges it follows.
+func (m *MemoryRateLimitStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(m.lastSweep) < window {
+		return
+	}
+	m.lastSweep = now
+
+	for key, bucket := range m.buckets {
+		if now.Sub(bucket.updated) >= bucket.window {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package extension_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/extension"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+func TestComplexityRateLimit(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	graphql.Now = func() time.Time { return now }
+	defer func() { graphql.Now = time.Now }()
+
+	h := testserver.New()
+	h.Use(&extension.ComplexityRateLimit{
+		Key: func(ctx context.Context, rc *graphql.OperationContext) string {
+			return string(graphql.GetRequestInfo(ctx).Header.Peek("X-Api-Key"))
+		},
+		Budget: 10,
+		Window: 10 * time.Second,
+	})
+	h.AddTransport(&transport.POST{})
+	h.SetCalculatedComplexity(4)
+
+	doKeyedRequest := func(key string, body string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("/graphql")
+		req.Header.SetMethod("POST")
+		req.Header.SetContentType("application/json")
+		req.Header.Set("X-Api-Key", key)
+		req.SetBody([]byte(body))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+		h.Handler()(&fctx)
+
+		resp := &fasthttp.Response{}
+		fctx.Response.CopyTo(resp)
+		return resp
+	}
+
+	t.Run("charges the complexity to the client", func(t *testing.T) {
+		resp := doKeyedRequest("a", `{"query":"{ name }"}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"data":{"name":"test"},"extensions":{"rateLimit":{"cost":4,"budget":10,"remaining":6,"resetAfter":4}}}`, string(resp.Body()))
+		require.Equal(t, "10", string(resp.Header.Peek("RateLimit-Limit")))
+		require.Equal(t, "6", string(resp.Header.Peek("RateLimit-Remaining")))
+		require.Equal(t, "4", string(resp.Header.Peek("RateLimit-Reset")))
+
+		resp = doKeyedRequest("a", `{"query":"{ name }"}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, "2", string(resp.Header.Peek("RateLimit-Remaining")))
+	})
+
+	t.Run("rejects operations over the remaining budget", func(t *testing.T) {
+		resp := doKeyedRequest("a", `{"query":"{ name }"}`)
+		require.Equal(t, fasthttp.StatusTooManyRequests, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"operation has complexity 4, which exceeds the remaining rate limit budget of 2","extensions":{"code":"RATE_LIMITED"}}],"data":null,"extensions":{"rateLimit":{"cost":4,"budget":10,"remaining":2,"resetAfter":8}}}`, string(resp.Body()))
+		require.Equal(t, "2", string(resp.Header.Peek("Retry-After")))
+
+		resp = doKeyedRequest("b", `{"query":"{ name }"}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, "6", string(resp.Header.Peek("RateLimit-Remaining")))
+	})
+
+	t.Run("refills the budget over the window", func(t *testing.T) {
+		now = now.Add(2 * time.Second)
+		resp := doKeyedRequest("a", `{"query":"{ name }"}`)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, "0", string(resp.Header.Peek("RateLimit-Remaining")))
+		require.Equal(t, "10", string(resp.Header.Peek("RateLimit-Reset")))
+	})
+
+	t.Run("batches get the tightest budget", func(t *testing.T) {
+		resp := doKeyedRequest("c", `[{"query":"{ name }"},{"query":"{ name }"},{"query":"{ name }"}]`)
+		require.Equal(t, "10", string(resp.Header.Peek("RateLimit-Limit")))
+		require.Equal(t, "2", string(resp.Header.Peek("RateLimit-Remaining")))
+		require.Equal(t, "2", string(resp.Header.Peek("Retry-After")))
+	})
+
+	t.Run("operations without a key are not limited", func(t *testing.T) {
+		resp := doKeyedRequest("", `{"query":"{ name }"}`)
+		require.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		require.Empty(t, resp.Header.Peek("RateLimit-Limit"))
+	})
+}
+
+type unavailableStore struct{}
+
+func (unavailableStore) Take(ctx context.Context, key string, cost, budget int, window time.Duration) (extension.RateLimitResult, error) {
+	return extension.RateLimitResult{}, errors.New("connection refused")
+}
+
+func TestComplexityRateLimitUnavailable(t *testing.T) {
+	h := testserver.New()
+	h.Use(&extension.ComplexityRateLimit{
+		Key: func(ctx context.Context, rc *graphql.OperationContext) string {
+			return "a"
+		},
+		Budget: 10,
+		Window: 10 * time.Second,
+		Store:  unavailableStore{},
+	})
+	h.AddTransport(&transport.POST{})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI("/graphql")
+	req.Header.SetMethod("POST")
+	req.Header.SetContentType("application/json")
+	req.SetBody([]byte(`{"query":"{ name }"}`))
+
+	var fctx fasthttp.RequestCtx
+	fctx.Init(req, nil, nil)
+	h.Handler()(&fctx)
+
+	require.Equal(t, fasthttp.StatusUnprocessableEntity, fctx.Response.StatusCode(), string(fctx.Response.Body()))
+	require.Equal(t, `{"errors":[{"message":"rate limit could not be checked: connection refused","extensions":{"code":"RATE_LIMIT_UNAVAILABLE"}}],"data":null}`, string(fctx.Response.Body()))
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/executor"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// HTTPHandler returns a net/http handler serving the same transports as Handler, so the server can be mounted behind
+// net/http middleware. Values that middleware put in the request context are visible to resolvers and extensions, and
+// operations are cancelled along with it.
+//
+// Multipart bodies are streamed to the MultipartForm transport, which enforces its own limits, the other bodies are
+// read in memory up to the fasthttp.DefaultMaxRequestBodySize a fasthttp.Server allows by default.
+//
+// Websocket upgrades need a ResponseWriter implementing http.Hijacker, the connection is handed over to fasthttp once
+// hijacked.
+func (s *Server) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		convertRequest(r, req)
+
+		var ctx fasthttp.RequestCtx
+		ctx.Init(req, remoteAddr(r), nil)
+		// the body is set once the request has been copied to ctx, which doesn't copy body streams
+		if err := convertBody(w, r, &ctx.Request); err == errRequestTooLarge {
+			writeHTTPError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		} else if err != nil {
+			writeHTTPError(w, http.StatusBadRequest, "request body could not be read")
+			return
+		}
+
+		exec := httpExecutor{exec: s.exec, ctx: r.Context()}
+
+		if ctx.Request.Header.ConnectionUpgrade() {
+			s.serveHijacked(w, &ctx.Request, exec)
+			return
+		}
+
+		s.serve(&ctx, exec)
+
+		writeResponse(w, &ctx.Response)
+	})
+}
+
+// serveHijacked takes over the connection of an upgrade request and serves it with fasthttp, which the websocket
+// transport needs to hijack the connection in turn.
+func (s *Server) serveHijacked(w http.ResponseWriter, req *fasthttp.Request, exec graphql.GraphExecutor) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeHTTPError(w, http.StatusInternalServerError, "connection upgrades need a ResponseWriter that implements http.Hijacker")
+		return
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, "connection could not be hijacked")
+		return
+	}
+
+	// the request has already been read from the connection, it is replayed ahead of anything the client sends next
+	var buf bytes.Buffer
+	if _, err := req.WriteTo(&buf); err != nil {
+		conn.Close()
+		return
+	}
+
+	_ = s.hijackedServer().ServeConn(&replayConn{Conn: conn, r: io.MultiReader(&buf, rw.Reader), exec: exec})
+}
+
+// hijackedServer returns the fasthttp server shared by the connections serveHijacked takes over, they carry the
+// executor of their request.
+func (s *Server) hijackedServer() *fasthttp.Server {
+	s.hijackedOnce.Do(func() {
+		s.hijacked = &fasthttp.Server{
+			Handler: func(ctx *fasthttp.RequestCtx) {
+				conn, ok := ctx.Conn().(*replayConn)
+				if !ok {
+					sendErrorf(ctx, http.StatusInternalServerError, "connection was not hijacked")
+					ctx.SetConnectionClose()
+					return
+				}
+				s.serve(ctx, conn.exec)
+				if !ctx.Hijacked() {
+					ctx.SetConnectionClose()
+				}
+			},
+		}
+	})
+	return s.hijacked
+}
+
+func convertRequest(r *http.Request, req *fasthttp.Request) {
+	req.Header.SetMethod(r.Method)
+	req.SetRequestURI(r.URL.RequestURI())
+	req.Header.SetHost(r.Host)
+
+	for key, values := range r.Header {
+		switch key {
+		case "Content-Length", "Transfer-Encoding":
+			// net/http has already decoded the body, its length is set along with it
+			continue
+		}
+		for i, value := range values {
+			if i == 0 {
+				req.Header.Set(key, value)
+			} else {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+
+}
+
+var errRequestTooLarge = errors.New("request body too large")
+
+// convertBody sets the body of r to req. Multipart bodies are left as a stream, the other transports need the whole
+// body, which is read up to fasthttp.DefaultMaxRequestBodySize.
+func convertBody(w http.ResponseWriter, r *http.Request, req *fasthttp.Request) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		req.SetBodyStream(r.Body, int(r.ContentLength))
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, fasthttp.DefaultMaxRequestBodySize))
+	if err != nil {
+		if len(body) >= fasthttp.DefaultMaxRequestBodySize {
+			return errRequestTooLarge
+		}
+		return err
+	}
+	req.SetBody(body)
+	return nil
+}
+
+func remoteAddr(r *http.Request) net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+func writeResponse(w http.ResponseWriter, resp *fasthttp.Response) {
+	resp.Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		case "Content-Length", "Transfer-Encoding", "Connection":
+			// framing is left to net/http
+			return
+		}
+		w.Header().Add(string(key), string(value))
+	})
+	w.WriteHeader(resp.StatusCode())
+
+	if !resp.IsBodyStream() {
+		_, _ = w.Write(resp.Body())
+		return
+	}
+
+	// streamed responses are flushed as they are written, a failed write stops the stream writer
+	if f, ok := w.(http.Flusher); ok {
+		_ = resp.BodyWriteTo(flushWriter{w: w, f: f})
+		return
+	}
+	_ = resp.BodyWriteTo(w)
+}
+
+func writeHTTPError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	b, _ := json.Marshal(&graphql.Response{Errors: gqlerror.List{{Message: msg}}})
+	_, _ = w.Write(b)
+}
+
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (w flushWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.f.Flush()
+	return n, err
+}
+
+// replayConn reads from r instead of the connection, r ends with the connection's own reader. exec serves the
+// requests of the connection.
+type replayConn struct {
+	net.Conn
+	r    io.Reader
+	exec graphql.GraphExecutor
+}
+
+func (c *replayConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// httpExecutor runs operations with a context that is also done once the net/http request context is, and that falls
+// back to it for values, so the ones set by net/http middleware reach resolvers.
+type httpExecutor struct {
+	exec *executor.Executor
+	ctx  context.Context
+}
+
+var _ graphql.GraphExecutor = httpExecutor{}
+
+func (e httpExecutor) CreateOperationContext(ctx context.Context, params *graphql.RawParams) (*graphql.OperationContext, gqlerror.List) {
+	return e.exec.CreateOperationContext(newHTTPContext(ctx, e.ctx), params)
+}
+
+func (e httpExecutor) DispatchOperation(ctx context.Context, rc *graphql.OperationContext) (graphql.ResponseHandler, context.Context) {
+	return e.exec.DispatchOperation(newHTTPContext(ctx, e.ctx), rc)
+}
+
+func (e httpExecutor) DispatchError(ctx context.Context, list gqlerror.List) *graphql.Response {
+	return e.exec.DispatchError(newHTTPContext(ctx, e.ctx), list)
+}
+
+// httpContext is the context of an operation served by HTTPHandler, it is done as soon as the operation context or the
+// net/http request context is.
+type httpContext struct {
+	context.Context
+	op   context.Context
+	http context.Context
+}
+
+// newHTTPContext merges ctx with the net/http request context. net/http cancels the request context once the handler
+// returns, which ends the goroutine watching it.
+func newHTTPContext(ctx context.Context, http context.Context) context.Context {
+	merged, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-http.Done():
+			cancel()
+		case <-merged.Done():
+		}
+	}()
+	return httpContext{Context: merged, op: ctx, http: http}
+}
+
+func (c httpContext) Deadline() (time.Time, bool) {
+	deadline, ok := c.Context.Deadline()
+	if httpDeadline, httpOk := c.http.Deadline(); httpOk && (!ok || httpDeadline.Before(deadline)) {
+		return httpDeadline, true
+	}
+	return deadline, ok
+}
+
+func (c httpContext) Err() error {
+	err := c.Context.Err()
+	if err == nil {
+		return nil
+	}
+	// the merged context only knows it has been cancelled, the context that is done tells why
+	if opErr := c.op.Err(); opErr != nil {
+		return opErr
+	}
+	if httpErr := c.http.Err(); httpErr != nil {
+		return httpErr
+	}
+	return err
+}
+
+func (c httpContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.http.Value(key)
+}
@@ -0,0 +1,232 @@
+package handler_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+type middlewareKey struct{}
+
+func TestHTTPHandler(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.Websocket{})
+	h.AddTransport(transport.SSE{HeartbeatInterval: 50 * time.Millisecond})
+	h.AddTransport(transport.GET{})
+	h.AddTransport(transport.POST{})
+	h.AddTransport(transport.MultipartForm{})
+
+	var seen interface{}
+	h.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		seen = ctx.Value(middlewareKey{})
+		return next(ctx)
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), middlewareKey{}, "from net/http"))
+		h.HTTPHandler().ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	t.Run("post", func(t *testing.T) {
+		seen = nil
+		resp, err := http.Post(srv.URL+"/graphql", "application/json", strings.NewReader(`{"query":"{ name }"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(b))
+		assert.Equal(t, "from net/http", seen)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/graphql?query=mutation{name}")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Equal(t, "POST", resp.Header.Get("Allow"))
+		assert.Equal(t, `{"errors":[{"message":"GET requests only allow query operations"}],"data":null}`, string(b))
+	})
+
+	t.Run("multipart form", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		require.NoError(t, mw.WriteField("operations", `{"query":"{ name }"}`))
+		require.NoError(t, mw.WriteField("map", `{}`))
+		require.NoError(t, mw.Close())
+
+		resp, err := http.Post(srv.URL+"/graphql", mw.FormDataContentType(), &body)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(b))
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		seen = nil
+		c, _, err := websocket.DefaultDialer.Dial(strings.Replace(srv.URL, "http://", "ws://", 1)+"/graphql", nil)
+		require.NoError(t, err)
+		defer c.Close()
+
+		require.NoError(t, c.SetReadDeadline(time.Now().Add(5*time.Second)))
+		require.NoError(t, c.WriteJSON(map[string]interface{}{"type": "connection_init"}))
+
+		var msg map[string]interface{}
+		require.NoError(t, c.ReadJSON(&msg))
+		assert.Equal(t, "connection_ack", msg["type"])
+
+		require.NoError(t, c.WriteJSON(map[string]interface{}{
+			"type":    "start",
+			"id":      "1",
+			"payload": map[string]interface{}{"query": "subscription { name }"},
+		}))
+
+		h.SendNextSubscriptionMessage()
+		require.NoError(t, c.ReadJSON(&msg))
+		for msg["type"] == "ka" {
+			require.NoError(t, c.ReadJSON(&msg))
+		}
+		assert.Equal(t, "data", msg["type"])
+		assert.Equal(t, "from net/http", seen)
+
+		// stop the subscription so it doesn't take the events of the next test
+		require.NoError(t, c.WriteJSON(map[string]interface{}{"type": "stop", "id": "1"}))
+		for msg["type"] != "complete" {
+			require.NoError(t, c.ReadJSON(&msg))
+		}
+	})
+
+	t.Run("server-sent events", func(t *testing.T) {
+		req, err := http.NewRequest("POST", srv.URL+"/graphql", strings.NewReader(`{"query":"subscription { name }"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		r := bufio.NewReader(resp.Body)
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, ":\n", line)
+
+		// the event arrives while the response is still open
+		h.SendNextSubscriptionMessage()
+		for line == ":\n" || line == "\n" {
+			line, err = r.ReadString('\n')
+			require.NoError(t, err)
+		}
+		assert.Equal(t, "event: next\n", line)
+	})
+}
+
+func TestHTTPHandlerRequestContext(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.POST{})
+
+	started := make(chan struct{})
+	var opErr error
+	var opDeadline time.Time
+	h.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		opDeadline, _ = ctx.Deadline()
+		close(started)
+		select {
+		case <-ctx.Done():
+			opErr = ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+		return next(ctx)
+	})
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	r := httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query":"{ name }"}`)).WithContext(ctx)
+	r.Header.Set("Content-Type", "application/json")
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	h.HTTPHandler().ServeHTTP(httptest.NewRecorder(), r)
+
+	require.True(t, deadline.Equal(opDeadline), "the operation has the deadline of the request")
+	require.Equal(t, context.Canceled, opErr)
+}
+
+func TestHTTPHandlerBodyLimits(t *testing.T) {
+	store := &transport.MemoryUploadStore{}
+	h := testserver.New()
+	h.AddTransport(transport.POST{})
+	h.AddTransport(transport.MultipartForm{MaxUploadSize: 1024, Store: store})
+
+	srv := httptest.NewServer(h.HTTPHandler())
+	defer srv.Close()
+
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	require.NoError(t, mw.WriteField("operations", `{"query":"mutation ($file: Upload!) { singleUpload(file: $file) { id } }","variables":{"file":null}}`))
+	require.NoError(t, mw.WriteField("map", `{"0":["variables.file"]}`))
+	w, err := mw.CreateFormFile("0", "a.txt")
+	require.NoError(t, err)
+	_, err = w.Write(bytes.Repeat([]byte("a"), 4096))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	post := func(contentType string, body io.Reader) (int, string) {
+		resp, err := http.Post(srv.URL+"/graphql", contentType, body)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	t.Run("multipart body over MaxUploadSize", func(t *testing.T) {
+		status, body := post(mw.FormDataContentType(), bytes.NewReader(form.Bytes()))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.Equal(t, `{"errors":[{"message":"failed to parse multipart form, request body too large"}],"data":null}`, body)
+	})
+
+	t.Run("multipart body of unknown length over MaxUploadSize", func(t *testing.T) {
+		// a reader net/http can't tell the length of is sent chunked
+		status, body := post(mw.FormDataContentType(), ioutil.NopCloser(bytes.NewReader(form.Bytes())))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.Equal(t, `{"errors":[{"message":"failed to parse multipart form, request body too large"}],"data":null}`, body)
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("other bodies over the fasthttp limit", func(t *testing.T) {
+		status, body := post("application/json", io.MultiReader(strings.NewReader(`{"query":"`), bytes.NewReader(make([]byte, fasthttp.DefaultMaxRequestBodySize))))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+		assert.Equal(t, `{"errors":[{"message":"request body too large"}],"data":null}`, body)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/sujamess/fastgql/graphql"
@@ -18,14 +19,21 @@ import (
 
 type (
 	Server struct {
-		transports []graphql.Transport
-		exec       *executor.Executor
+		transports           []graphql.Transport
+		exec                 *executor.Executor
+		shutdown             *graphql.Shutdown
+		maxOperationDuration time.Duration
+
+		// hijacked serves the upgrade requests of HTTPHandler once their connection has been taken over
+		hijacked     *fasthttp.Server
+		hijackedOnce sync.Once
 	}
 )
 
 func New(es graphql.ExecutableSchema) *Server {
 	return &Server{
-		exec: executor.New(es),
+		exec:     executor.New(es),
+		shutdown: graphql.NewShutdown(),
 	}
 }
 
@@ -66,6 +74,12 @@ func (s *Server) SetQueryCache(cache graphql.Cache) {
 	s.exec.SetQueryCache(cache)
 }
 
+// SetMaxOperationDuration limits how long the queries and mutations sent over POST and GET may run, their resolvers
+// see their context cancelled once d has passed. They aren't limited by default.
+func (s *Server) SetMaxOperationDuration(d time.Duration) {
+	s.maxOperationDuration = d
+}
+
 func (s *Server) Use(extension graphql.HandlerExtension) {
 	s.exec.Use(extension)
 }
@@ -85,6 +99,17 @@ func (s *Server) AroundResponses(f graphql.ResponseMiddleware) {
 	s.exec.AroundResponses(f)
 }
 
+// Shutdown gracefully stops the server. New requests are answered with 503, websocket subscriptions are completed
+// and their connections closed once the queries and mutations they run are over, and subscription resolvers can
+// watch graphql.ShuttingDown to end their subscriptions. It then waits for the requests in flight, along with the
+// responses they still stream like the ones of SSE and @defer, until ctx is done, in which case the operations of the
+// POST and GET requests still running are cancelled and it returns the error of ctx.
+//
+// Call it before shutting down the fasthttp server, which doesn't wait for hijacked websocket connections.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.shutdown.Start(ctx)
+}
+
 func (s *Server) getTransport(ctx *fasthttp.RequestCtx) graphql.Transport {
 	for _, t := range s.transports {
 		if t.Supports(ctx) {
@@ -96,26 +121,42 @@ func (s *Server) getTransport(ctx *fasthttp.RequestCtx) graphql.Transport {
 
 func (s *Server) Handler() fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
-		defer func() {
-			if err := recover(); err != nil {
-				err := s.exec.PresentRecoveredError(ctx, err)
-				resp := &graphql.Response{Errors: []*gqlerror.Error{err}}
-				b, _ := json.Marshal(resp)
-				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-				ctx.Write(b)
-			}
-		}()
-
-		graphql.StartOperationTrace(ctx)
-
-		transport := s.getTransport(ctx)
-		if transport == nil {
-			sendErrorf(ctx, http.StatusBadRequest, "transport not supported")
-			return
+		s.serve(ctx, s.exec)
+	}
+}
+
+func (s *Server) serve(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
+	defer func() {
+		if err := recover(); err != nil {
+			err := s.exec.PresentRecoveredError(ctx, err)
+			resp := &graphql.Response{Errors: []*gqlerror.Error{err}}
+			b, _ := json.Marshal(resp)
+			ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+			ctx.Write(b)
 		}
+	}()
+
+	if !s.shutdown.Acquire() {
+		ctx.SetConnectionClose()
+		sendErrorf(ctx, http.StatusServiceUnavailable, "server is shutting down")
+		return
+	}
+	defer s.shutdown.Release()
 
-		transport.Do(ctx, s.exec)
+	graphql.StartOperationTrace(ctx)
+	graphql.WithRequestCtx(ctx)
+	graphql.WithShutdown(ctx, s.shutdown)
+	if s.maxOperationDuration > 0 {
+		graphql.WithMaxOperationDuration(ctx, s.maxOperationDuration)
 	}
+
+	transport := s.getTransport(ctx)
+	if transport == nil {
+		sendErrorf(ctx, http.StatusBadRequest, "transport not supported")
+		return
+	}
+
+	transport.Do(ctx, exec)
 }
 
 func sendError(ctx *fasthttp.RequestCtx, code int, errors ...*gqlerror.Error) {
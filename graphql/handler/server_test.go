@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,13 +38,13 @@ func TestServer(t *testing.T) {
 
 	t.Run("mutations are forbidden", func(t *testing.T) {
 		resp := get(h, "/foo?query=mutation{name}")
-		assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode())
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode())
 		assert.Equal(t, `{"errors":[{"message":"GET requests only allow query operations"}],"data":null}`, string(resp.Body()))
 	})
 
 	t.Run("subscriptions are forbidden", func(t *testing.T) {
 		resp := get(h, "/foo?query=subscription{name}")
-		assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode())
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode())
 		assert.Equal(t, `{"errors":[{"message":"GET requests only allow query operations"}],"data":null}`, string(resp.Body()))
 	})
 
@@ -143,6 +144,23 @@ func TestServer(t *testing.T) {
 	})
 }
 
+func TestMaxOperationDuration(t *testing.T) {
+	srv := testserver.New()
+	srv.AddTransport(&transport.GET{})
+	srv.SetMaxOperationDuration(50 * time.Millisecond)
+
+	var err error
+	srv.AroundFields(func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+		<-ctx.Done()
+		err = ctx.Err()
+		return next(ctx)
+	})
+
+	resp := get(srv.Handler(), "/foo?query={name}")
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
 func TestErrorServer(t *testing.T) {
 	srv := testserver.NewError()
 	srv.AddTransport(&transport.GET{})
@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+func TestShutdown(t *testing.T) {
+	srv := testserver.New()
+	srv.AddTransport(&transport.GET{})
+
+	started := make(chan (<-chan struct{}))
+	release := make(chan struct{})
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		started <- graphql.ShuttingDown(ctx)
+		<-release
+		return next(ctx)
+	})
+
+	h := srv.Handler()
+
+	inflight := make(chan *fasthttp.Response)
+	go func() {
+		inflight <- get(h, "/foo?query={name}")
+	}()
+	shuttingDown := <-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, srv.Shutdown(ctx))
+
+	t.Run("resolvers observe the shutdown", func(t *testing.T) {
+		select {
+		case <-shuttingDown:
+		default:
+			t.Fatal("shutdown signal wasn't sent")
+		}
+	})
+
+	t.Run("new requests are refused", func(t *testing.T) {
+		resp := get(h, "/foo?query={name}")
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+		assert.Equal(t, `{"errors":[{"message":"server is shutting down"}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("requests in flight are waited for", func(t *testing.T) {
+		close(release)
+		require.NoError(t, srv.Shutdown(context.Background()))
+
+		resp := <-inflight
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+}
+
+func TestShutdownCancelsOperations(t *testing.T) {
+	srv := testserver.New()
+	srv.AddTransport(&transport.GET{})
+
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	srv.AroundFields(func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return next(ctx)
+	})
+
+	inflight := make(chan *fasthttp.Response)
+	go func() {
+		inflight <- get(srv.Handler(), "/foo?query={name}")
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, srv.Shutdown(ctx))
+
+	assert.Equal(t, context.Canceled, <-cancelled)
+	<-inflight
+	require.NoError(t, srv.Shutdown(context.Background()))
+}
+
+func TestShutdownWaitsForStreams(t *testing.T) {
+	srv := testserver.New()
+	srv.AddTransport(transport.SSE{})
+
+	// the subscription only ends once released after the shutdown started
+	release := make(chan struct{})
+	srv.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		resp := next(ctx)
+		if resp == nil {
+			<-release
+		}
+		return resp
+	})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI("/graphql?query=subscription{name}")
+	req.Header.SetMethod("GET")
+	req.Header.Set("Accept", "text/event-stream")
+
+	var fctx fasthttp.RequestCtx
+	fctx.Init(req, nil, nil)
+	srv.Handler()(&fctx)
+	require.True(t, fctx.Response.IsBodyStream())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, srv.Shutdown(ctx))
+
+	close(release)
+	require.NoError(t, srv.Shutdown(context.Background()))
+	assert.Contains(t, string(fctx.Response.Body()), "event: complete")
+}
@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Compression compresses responses with the best encoding the client accepts in Accept-Encoding, brotli being
+// preferred over gzip and deflate. Streamed responses are sent uncompressed.
+type Compression struct {
+	// MinSize sets the size in bytes under which responses are sent uncompressed, as compressing them isn't worth
+	// it. Defaults to 1024.
+	MinSize int
+
+	// Level sets the gzip and deflate compression level, between fasthttp.CompressBestSpeed and
+	// fasthttp.CompressBestCompression. Defaults to fasthttp.CompressDefaultCompression.
+	Level int
+
+	// BrotliLevel sets the brotli compression level, between fasthttp.CompressBrotliBestSpeed and
+	// fasthttp.CompressBrotliBestCompression. Defaults to fasthttp.CompressBrotliDefaultCompression.
+	BrotliLevel int
+}
+
+func (c *Compression) minSize() int {
+	if c.MinSize == 0 {
+		return 1024
+	}
+	return c.MinSize
+}
+
+func (c *Compression) level() int {
+	if c.Level == 0 {
+		return fasthttp.CompressDefaultCompression
+	}
+	return c.Level
+}
+
+func (c *Compression) brotliLevel() int {
+	if c.BrotliLevel == 0 {
+		return fasthttp.CompressBrotliDefaultCompression
+	}
+	return c.BrotliLevel
+}
+
+// compressResponse compresses the body written to ctx when a policy is set and the client accepts one of the
+// supported encodings. Whether it is compressed or not, the response depends on Accept-Encoding, which Vary tells
+// caches.
+func compressResponse(c *Compression, ctx *fasthttp.RequestCtx) {
+	if c == nil || ctx.Response.IsBodyStream() {
+		return
+	}
+	ctx.Response.Header.Add("Vary", "Accept-Encoding")
+
+	body := ctx.Response.Body()
+	if len(body) < c.minSize() {
+		return
+	}
+
+	encoding := acceptedEncoding(string(ctx.Request.Header.Peek("Accept-Encoding")))
+	if encoding == "" {
+		return
+	}
+
+	var compressed []byte
+	switch encoding {
+	case "br":
+		compressed = fasthttp.AppendBrotliBytesLevel(nil, body, c.brotliLevel())
+	case "gzip":
+		compressed = fasthttp.AppendGzipBytesLevel(nil, body, c.level())
+	case "deflate":
+		compressed = fasthttp.AppendDeflateBytesLevel(nil, body, c.level())
+	}
+
+	ctx.Response.SetBodyRaw(compressed)
+	ctx.Response.Header.Set("Content-Encoding", encoding)
+}
+
+// acceptedEncoding picks the encoding with the highest quality out of an Accept-Encoding header, preferring br, gzip
+// and deflate in that order when qualities are equal. It returns an empty string if none of them is accepted.
+func acceptedEncoding(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(params[0]))
+		if encoding == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err != nil {
+					q = 0
+				}
+				quality = q
+			}
+		}
+		qualities[encoding] = quality
+	}
+
+	var best string
+	var bestQuality float64
+	for _, encoding := range []string{"br", "gzip", "deflate"} {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
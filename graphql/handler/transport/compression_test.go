@@ -0,0 +1,111 @@
+package transport_test
+
+import (
+	"testing"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCompression(t *testing.T) {
+	h := testserver.New()
+	compression := &transport.Compression{MinSize: 10}
+	h.AddTransport(transport.GET{Compression: compression})
+	h.AddTransport(transport.POST{Compression: compression})
+
+	doCompressedRequest := func(method string, acceptEncoding string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("/graphql?query={name}")
+		req.Header.SetMethod(method)
+		req.Header.SetContentType("application/json")
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		req.SetBody([]byte(`{"query":"{ name }"}`))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		h.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		resp := doCompressedRequest("POST", "gzip, deflate")
+		assert.Equal(t, "gzip", string(resp.Header.Peek("Content-Encoding")))
+		assert.Equal(t, "Accept-Encoding", string(resp.Header.Peek("Vary")))
+		body, err := resp.BodyGunzip()
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(body))
+	})
+
+	t.Run("brotli is preferred", func(t *testing.T) {
+		resp := doCompressedRequest("GET", "deflate, gzip, br")
+		assert.Equal(t, "br", string(resp.Header.Peek("Content-Encoding")))
+		body, err := resp.BodyUnbrotli()
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(body))
+	})
+
+	t.Run("quality", func(t *testing.T) {
+		resp := doCompressedRequest("POST", "br;q=0.5, deflate")
+		assert.Equal(t, "deflate", string(resp.Header.Peek("Content-Encoding")))
+		body, err := resp.BodyInflate()
+		require.NoError(t, err)
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(body))
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		resp := doCompressedRequest("POST", "compress, br;q=0")
+		assert.Empty(t, resp.Header.Peek("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", string(resp.Header.Peek("Vary")))
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+
+	t.Run("small responses", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.POST{Compression: &transport.Compression{}})
+
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ name }"}`)
+		assert.Empty(t, resp.Header.Peek("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", string(resp.Header.Peek("Vary")))
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+}
+
+func TestWebsocketCompression(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.Websocket{
+		Upgrader:         websocket.FastHTTPUpgrader{EnableCompression: true},
+		CompressionLevel: 9,
+	})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	c, resp, err := dialer.Dial("ws://"+ln.Addr().String()+"/graphql", nil)
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Contains(t, resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+
+	require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+	assert.Equal(t, connectionAckMsg, readOp(c).Type)
+	assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+	require.NoError(t, c.WriteJSON(&operationMessage{
+		Type:    startMsg,
+		ID:      "test_1",
+		Payload: []byte(`{"query": "subscription { name }"}`),
+	}))
+
+	h.SendNextSubscriptionMessage()
+	msg := readOp(c)
+	require.Equal(t, dataMsg, msg.Type, string(msg.Payload))
+	require.Equal(t, `{"data":{"name":"test"}}`, string(msg.Payload))
+}
@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CORS is a cross-origin resource sharing policy, see https://fetch.spec.whatwg.org/#http-cors-protocol
+//
+// Options uses it to answer preflight requests, the other HTTP transports to add the matching headers to actual
+// responses. Share a single policy between them.
+type CORS struct {
+	// AllowedOrigins lists the origins allowed to call the API, like "https://example.com". An origin may hold a single
+	// "*" wildcard, like "https://*.example.com", and "*" alone allows any origin.
+	//
+	// "*" alone is ignored when AllowCredentials is set: letting any site send requests with the cookies of the user
+	// would defeat the policy, list the origins instead.
+	AllowedOrigins []string
+
+	// AllowedMethods lists the methods allowed in cross-origin requests. Defaults to GET and POST.
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers allowed in cross-origin requests, "*" allows any header. Defaults to
+	// Content-Type.
+	AllowedHeaders []string
+
+	// AllowCredentials lets browsers send cookies and HTTP authentication along with cross-origin requests.
+	AllowCredentials bool
+
+	// ExposedHeaders lists the response headers, other than the CORS-safelisted ones, that scripts can read from
+	// cross-origin responses, like "RateLimit-Remaining".
+	ExposedHeaders []string
+
+	// MaxAge sets how long browsers may cache the result of a preflight request. Browsers use their own default when
+	// it is zero.
+	MaxAge time.Duration
+}
+
+func (c *CORS) allowedMethods() []string {
+	if len(c.AllowedMethods) == 0 {
+		return []string{fasthttp.MethodGet, fasthttp.MethodPost}
+	}
+	return c.AllowedMethods
+}
+
+func (c *CORS) allowedHeaders() []string {
+	if len(c.AllowedHeaders) == 0 {
+		return []string{"Content-Type"}
+	}
+	return c.AllowedHeaders
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for origin, or an empty string if the
+// origin isn't allowed.
+func (c *CORS) allowOrigin(origin string) string {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			// browsers refuse the wildcard with credentials, echoing the origin instead would allow any site
+			if c.AllowCredentials {
+				continue
+			}
+			return "*"
+		}
+
+		if i := strings.IndexByte(allowed, '*'); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return origin
+			}
+			continue
+		}
+
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// setHeaders adds the headers shared by preflight and actual responses. It reports whether the origin is allowed
+// and the response got them.
+func (c *CORS) setHeaders(ctx *fasthttp.RequestCtx, allowed bool) bool {
+	ctx.Response.Header.Add("Vary", "Origin")
+
+	origin := string(ctx.Request.Header.Peek("Origin"))
+	if origin == "" || !allowed {
+		return false
+	}
+
+	allowOrigin := c.allowOrigin(origin)
+	if allowOrigin == "" {
+		return false
+	}
+
+	ctx.Response.Header.Set("Access-Control-Allow-Origin", allowOrigin)
+	if c.AllowCredentials {
+		ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+// applyCORS adds the CORS headers of an actual response when a policy is set.
+func applyCORS(c *CORS, ctx *fasthttp.RequestCtx) {
+	if c != nil && c.setHeaders(ctx, true) && len(c.ExposedHeaders) > 0 {
+		ctx.Response.Header.Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+	}
+}
+
+// preflight answers a CORS preflight request. Requests that aren't allowed get no CORS headers, which makes the
+// browser reject them.
+func (c *CORS) preflight(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.Response.Header.Add("Vary", "Access-Control-Request-Method")
+	ctx.Response.Header.Add("Vary", "Access-Control-Request-Headers")
+
+	method := string(ctx.Request.Header.Peek("Access-Control-Request-Method"))
+	if !c.setHeaders(ctx, containsFold(c.allowedMethods(), method)) {
+		return
+	}
+
+	ctx.Response.Header.Set("Access-Control-Allow-Methods", strings.Join(c.allowedMethods(), ", "))
+
+	allowedHeaders := c.allowedHeaders()
+	if !containsFold(allowedHeaders, "*") {
+		ctx.Response.Header.Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+	} else if requested := string(ctx.Request.Header.Peek("Access-Control-Request-Headers")); requested != "" {
+		ctx.Response.Header.Set("Access-Control-Allow-Headers", requested)
+	}
+
+	if c.MaxAge > 0 {
+		ctx.Response.Header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge/time.Second)))
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
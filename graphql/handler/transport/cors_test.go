@@ -0,0 +1,130 @@
+package transport_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCORS(t *testing.T) {
+	cors := &transport.CORS{
+		AllowedOrigins:   []string{"https://example.com", "https://*.example.org"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		ExposedHeaders:   []string{"RateLimit-Remaining", "RateLimit-Reset"},
+		MaxAge:           10 * time.Minute,
+	}
+
+	h := testserver.New()
+	h.AddTransport(transport.Options{CORS: cors})
+	h.AddTransport(transport.GET{CORS: cors})
+	h.AddTransport(transport.POST{CORS: cors})
+
+	doCORSRequest := func(method string, origin string, headers map[string]string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("/graphql?query={name}")
+		req.Header.SetMethod(method)
+		req.Header.Set("Origin", origin)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		h.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	t.Run("preflight from an allowed origin", func(t *testing.T) {
+		resp := doCORSRequest("OPTIONS", "https://example.com", map[string]string{
+			"Access-Control-Request-Method":  "POST",
+			"Access-Control-Request-Headers": "content-type",
+		})
+		assert.Equal(t, fasthttp.StatusNoContent, resp.StatusCode())
+		assert.Equal(t, "https://example.com", string(resp.Header.Peek("Access-Control-Allow-Origin")))
+		assert.Equal(t, "GET, POST", string(resp.Header.Peek("Access-Control-Allow-Methods")))
+		assert.Equal(t, "Content-Type, Authorization", string(resp.Header.Peek("Access-Control-Allow-Headers")))
+		assert.Equal(t, "true", string(resp.Header.Peek("Access-Control-Allow-Credentials")))
+		assert.Equal(t, "600", string(resp.Header.Peek("Access-Control-Max-Age")))
+	})
+
+	t.Run("preflight from a wildcard origin", func(t *testing.T) {
+		resp := doCORSRequest("OPTIONS", "https://api.example.org", map[string]string{"Access-Control-Request-Method": "GET"})
+		assert.Equal(t, "https://api.example.org", string(resp.Header.Peek("Access-Control-Allow-Origin")))
+
+		resp = doCORSRequest("OPTIONS", "https://example.org", map[string]string{"Access-Control-Request-Method": "GET"})
+		assert.Empty(t, resp.Header.Peek("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight from another origin", func(t *testing.T) {
+		resp := doCORSRequest("OPTIONS", "https://evil.com", map[string]string{"Access-Control-Request-Method": "POST"})
+		assert.Equal(t, fasthttp.StatusNoContent, resp.StatusCode())
+		assert.Empty(t, resp.Header.Peek("Access-Control-Allow-Origin"))
+		assert.Empty(t, resp.Header.Peek("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("preflight for a method that isn't allowed", func(t *testing.T) {
+		resp := doCORSRequest("OPTIONS", "https://example.com", map[string]string{"Access-Control-Request-Method": "DELETE"})
+		assert.Empty(t, resp.Header.Peek("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("actual response", func(t *testing.T) {
+		resp := doCORSRequest("GET", "https://example.com", nil)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		assert.Equal(t, "https://example.com", string(resp.Header.Peek("Access-Control-Allow-Origin")))
+		assert.Equal(t, "true", string(resp.Header.Peek("Access-Control-Allow-Credentials")))
+		assert.Equal(t, "Origin", string(resp.Header.Peek("Vary")))
+		assert.Equal(t, "RateLimit-Remaining, RateLimit-Reset", string(resp.Header.Peek("Access-Control-Expose-Headers")))
+
+		resp = doCORSRequest("GET", "https://evil.com", nil)
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		assert.Empty(t, resp.Header.Peek("Access-Control-Allow-Origin"))
+		assert.Empty(t, resp.Header.Peek("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("any origin", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.GET{CORS: &transport.CORS{AllowedOrigins: []string{"*"}}})
+
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		req.SetRequestURI("/graphql?query={name}")
+		req.Header.Set("Origin", "https://anywhere.com")
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+		h.Handler()(&fctx)
+
+		assert.Equal(t, "*", string(fctx.Response.Header.Peek("Access-Control-Allow-Origin")))
+	})
+
+	t.Run("any origin is ignored with credentials", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.GET{CORS: &transport.CORS{
+			AllowedOrigins:   []string{"*", "https://example.com"},
+			AllowCredentials: true,
+		}})
+
+		for origin, allowOrigin := range map[string]string{"https://anywhere.com": "", "https://example.com": "https://example.com"} {
+			req := fasthttp.AcquireRequest()
+			req.SetRequestURI("/graphql?query={name}")
+			req.Header.Set("Origin", origin)
+
+			var fctx fasthttp.RequestCtx
+			fctx.Init(req, nil, nil)
+			fasthttp.ReleaseRequest(req)
+			h.Handler()(&fctx)
+
+			assert.Equal(t, allowOrigin, string(fctx.Response.Header.Peek("Access-Control-Allow-Origin")), origin)
+		}
+	})
+}
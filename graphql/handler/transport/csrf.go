@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CSRF blocks the requests a browser sends cross-origin without a preflight, see
+// https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS#simple_requests
+//
+// GET and multipart/form-data requests are simple requests, a malicious page can make the browser of a victim send
+// them along with its cookies. With a policy set, GET and MultipartForm only accept requests that either have a
+// Content-Type other than application/x-www-form-urlencoded, multipart/form-data and text/plain, or a non-empty
+// required header. Browsers preflight both, so CORS applies to them.
+type CSRF struct {
+	// RequiredHeaders lists the headers that let a request through, any of them is enough. Defaults to
+	// Apollo-Require-Preflight and X-Apollo-Operation-Name, which Apollo clients can send.
+	RequiredHeaders []string
+}
+
+func (c *CSRF) requiredHeaders() []string {
+	if len(c.RequiredHeaders) == 0 {
+		return []string{"Apollo-Require-Preflight", "X-Apollo-Operation-Name"}
+	}
+	return c.RequiredHeaders
+}
+
+// preventCSRF answers requests that could be forged cross-origin with 400 when a policy is set. It reports whether
+// the request may go on.
+func preventCSRF(c *CSRF, ctx *fasthttp.RequestCtx) bool {
+	if c == nil {
+		return true
+	}
+
+	if contentType := string(ctx.Request.Header.ContentType()); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		// browsers preflight content types they can't parse too
+		if err != nil || !isSimpleContentType(mediaType) {
+			return true
+		}
+	}
+
+	required := c.requiredHeaders()
+	for _, header := range required {
+		if len(ctx.Request.Header.Peek(header)) > 0 {
+			return true
+		}
+	}
+
+	ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+	writeJsonErrorf(ctx, "this operation has been blocked as a potential cross-site request forgery, "+
+		"send it with a Content-Type other than application/x-www-form-urlencoded, multipart/form-data or text/plain, "+
+		"or with one of these headers: %s", strings.Join(required, ", "))
+	return false
+}
+
+func isSimpleContentType(mediaType string) bool {
+	switch strings.ToLower(mediaType) {
+	case "application/x-www-form-urlencoded", "multipart/form-data", "text/plain":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package transport_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCSRF(t *testing.T) {
+	csrf := &transport.CSRF{}
+
+	h := testserver.New()
+	h.AddTransport(transport.GET{CSRF: csrf})
+	h.AddTransport(transport.MultipartForm{CSRF: csrf})
+
+	doRequest := func(method string, contentType string, body []byte, headers map[string]string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("/graphql?query={name}")
+		req.Header.SetMethod(method)
+		if contentType != "" {
+			req.Header.SetContentType(contentType)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		req.SetBody(body)
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		h.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	require.NoError(t, mw.WriteField("operations", `{"query":"{ name }"}`))
+	require.NoError(t, mw.WriteField("map", `{}`))
+	require.NoError(t, mw.Close())
+
+	t.Run("blocks simple requests", func(t *testing.T) {
+		for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded; charset=utf-8"} {
+			resp := doRequest("GET", contentType, nil, nil)
+			assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode(), contentType)
+			assert.Equal(t, `{"errors":[{"message":"this operation has been blocked as a potential cross-site request forgery, send it with a Content-Type other than application/x-www-form-urlencoded, multipart/form-data or text/plain, or with one of these headers: Apollo-Require-Preflight, X-Apollo-Operation-Name"}],"data":null}`, string(resp.Body()))
+		}
+
+		resp := doRequest("POST", mw.FormDataContentType(), form.Bytes(), nil)
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode())
+	})
+
+	t.Run("allows requests with a non-simple content type", func(t *testing.T) {
+		resp := doRequest("GET", "application/json", nil, nil)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+
+	t.Run("allows requests with a required header", func(t *testing.T) {
+		resp := doRequest("GET", "", nil, map[string]string{"Apollo-Require-Preflight": "true"})
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+
+		resp = doRequest("POST", mw.FormDataContentType(), form.Bytes(), map[string]string{"X-Apollo-Operation-Name": "Name"})
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+
+	t.Run("custom required headers", func(t *testing.T) {
+		csrf.RequiredHeaders = []string{"X-Requested-With"}
+		defer func() { csrf.RequiredHeaders = nil }()
+
+		resp := doRequest("GET", "", nil, map[string]string{"Apollo-Require-Preflight": "true"})
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode())
+
+		resp = doRequest("GET", "", nil, map[string]string{"X-Requested-With": "XMLHttpRequest"})
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+	})
+}
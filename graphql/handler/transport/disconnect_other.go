@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package transport
+
+import "net"
+
+// clientGone can't tell whether the client closed conn on this platform, the operations of clients that went away
+// only end with the server shutdown or MaxOperationDuration.
+func clientGone(conn net.Conn) (gone bool, ok bool) {
+	return false, false
+}
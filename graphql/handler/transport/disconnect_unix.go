@@ -0,0 +1,43 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package transport
+
+import (
+	"net"
+	"syscall"
+)
+
+// clientGone reports whether the client closed conn. It peeks at the socket, so the next request the client may have
+// sent already is left for the server to read. ok is false when conn doesn't expose its socket, as TLS connections
+// don't.
+func clientGone(conn net.Conn) (gone bool, ok bool) {
+	sc, isSyscallConn := conn.(syscall.Conn)
+	if !isSyscallConn {
+		return false, false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false, false
+	}
+
+	var buf [1]byte
+	err = raw.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		switch err {
+		case nil:
+			// nothing to read on an open socket would have been EAGAIN
+			gone = n == 0
+		case syscall.EAGAIN, syscall.EINTR:
+		default:
+			gone = true
+		}
+		// never wait for the socket to be readable
+		return true
+	})
+	if err != nil {
+		// the server closed the connection already
+		return true, true
+	}
+	return gone, true
+}
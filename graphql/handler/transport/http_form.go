@@ -2,10 +2,14 @@ package transport
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime"
+	"mime/multipart"
 	"os"
 	"strings"
 
@@ -14,15 +18,43 @@ import (
 )
 
 // MultipartForm the Multipart request spec https://github.com/jaydenseric/graphql-multipart-request-spec
+//
+// The form is parsed as it is read when the request body is a stream, which needs a server with StreamRequestBody
+// enabled, otherwise fasthttp reads the whole body in memory before the handler runs, up to the MaxRequestBodySize of
+// the server.
 type MultipartForm struct {
 	// MaxUploadSize sets the maximum number of bytes used to parse a request body
 	// as multipart/form-data.
 	MaxUploadSize int64
 
-	// MaxMemory defines the maximum number of bytes used to parse a request body
-	// as multipart/form-data in memory, with the remainder stored on disk in
-	// temporary files.
+	// MaxMemory sets the size under which the files of a request are kept in
+	// a MemoryUploadStore rather than a DiskUploadStore, requests whose size
+	// isn't known up front go to disk. It is only used when Store is nil.
 	MaxMemory int64
+
+	// Store holds the uploaded files while the operation runs. Defaults to a
+	// MemoryUploadStore for requests smaller than MaxMemory and to a
+	// DiskUploadStore in os.TempDir for bigger ones.
+	Store UploadStore
+
+	// MaxFileSize sets the maximum size in bytes of a single file, 0 means no
+	// limit other than MaxUploadSize.
+	MaxFileSize int64
+
+	// MaxFiles sets the maximum number of files in a request, 0 means no limit.
+	MaxFiles int
+
+	// AllowedContentTypes lists the content types files may have, like
+	// "image/png", or "image/*" for a whole type. Any content type is allowed
+	// when it is empty.
+	AllowedContentTypes []string
+
+	// CORS adds the Access-Control-* headers of the policy to responses, use the same policy as Options.
+	CORS *CORS
+
+	// CSRF rejects the requests a browser could send cross-origin without a
+	// preflight, use the same policy as GET.
+	CSRF *CSRF
 }
 
 var _ graphql.Transport = MultipartForm{}
@@ -54,43 +86,152 @@ func (f MultipartForm) maxMemory() int64 {
 	return f.MaxMemory
 }
 
+func (f MultipartForm) store(ctx *fasthttp.RequestCtx) UploadStore {
+	if f.Store != nil {
+		return f.Store
+	}
+	if size := int64(ctx.Request.Header.ContentLength()); size >= 0 && size < f.maxMemory() {
+		return &MemoryUploadStore{}
+	}
+	return DiskUploadStore{Dir: os.TempDir()}
+}
+
+func (f MultipartForm) allowsContentType(contentType string) bool {
+	if len(f.AllowedContentTypes) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range f.AllowedContentTypes {
+		if strings.EqualFold(allowed, mediaType) || allowed == "*/*" {
+			return true
+		}
+		if strings.HasSuffix(allowed, "/*") && len(mediaType) > len(allowed)-1 && strings.EqualFold(allowed[:len(allowed)-1], mediaType[:len(allowed)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
 func (f MultipartForm) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
-	ctx.Response.Header.SetContentType("application/json")
+	applyCORS(f.CORS, ctx)
+
+	contentType, ok := negotiateContentType(ctx)
+	if !ok || !preventCSRF(f.CSRF, ctx) {
+		return
+	}
 
 	start := graphql.Now()
 
-	var err error
 	if int64(ctx.Request.Header.ContentLength()) > f.maxUploadSize() {
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
 		writeJsonError(ctx, "failed to parse multipart form, request body too large")
 		return
 	}
 
-	if _, err = ctx.MultipartForm(); err != nil {
+	body, err := multipartBody(ctx)
+	if err != nil {
 		ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-		if strings.Contains(err.Error(), "request body too large") {
-			writeJsonError(ctx, "failed to parse multipart form, request body too large")
-			return
-		}
 		writeJsonError(ctx, "failed to parse multipart form")
 		return
 	}
+	defer body.Close()
+
+	// the stored files and the readers handed to resolvers only live as long as the operation, which may outlive the
+	// handler when it has incremental payloads
+	form := multipartUploads{uploads: f.store(ctx)}
+	cleanup := form.cleanup
+	defer func() {
+		if cleanup != nil {
+			cleanup()
+		}
+	}()
 
 	var params graphql.RawParams
+	var uploadsMap map[string][]string
+	seenOperations, seenMap := false, false
+
+	reader := multipart.NewReader(&limitedReader{r: body, n: f.maxUploadSize(), err: errBodyTooLarge}, string(ctx.Request.Header.MultipartFormBoundary()))
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if strings.Contains(err.Error(), errBodyTooLarge.Error()) {
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+				writeJsonError(ctx, "failed to parse multipart form, request body too large")
+				return
+			}
+			ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+			writeJsonError(ctx, "failed to parse multipart form")
+			return
+		}
+
+		switch key := part.FormName(); {
+		case key == "operations":
+			seenOperations = true
+			if err = jsonDecode(part, &params); err != nil {
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+				writeJsonError(ctx, "operations form field could not be decoded")
+				return
+			}
+
+		case key == "map":
+			seenMap = true
+			if err = json.NewDecoder(part).Decode(&uploadsMap); err != nil {
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+				writeJsonError(ctx, "map form field could not be decoded")
+				return
+			}
+
+		case uploadsMap[key] != nil && form.files[key] == nil:
+			// the spec sends the operations and the map ahead of the files, parts that aren't mapped are skipped
+			if f.MaxFiles > 0 && len(form.files) >= f.MaxFiles {
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+				writeJsonErrorf(ctx, "too many files, at most %d are allowed", f.MaxFiles)
+				return
+			}
+
+			partContentType := part.Header.Get("Content-Type")
+			if !f.allowsContentType(partContentType) {
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnsupportedMediaType)
+				writeJsonErrorf(ctx, "content type %s is not allowed for key %s", partContentType, key)
+				return
+			}
+
+			if err = form.store(key, part, f.MaxFileSize); err != nil {
+				if err == errFileTooLarge {
+					ctx.Response.Header.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+					writeJsonErrorf(ctx, "file for key %s is larger than %d bytes", key, f.MaxFileSize)
+					return
+				}
+				if err == errBodyTooLarge {
+					ctx.Response.Header.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+					writeJsonError(ctx, "failed to parse multipart form, request body too large")
+					return
+				}
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+				writeJsonErrorf(ctx, "failed to store file for key %s", key)
+				return
+			}
+		}
+	}
 
-	if err = jsonDecode(bytes.NewReader(ctx.FormValue("operations")), &params); err != nil {
+	if !seenOperations {
 		ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
 		writeJsonError(ctx, "operations form field could not be decoded")
 		return
 	}
-
-	var uploadsMap = map[string][]string{}
-	if err = json.Unmarshal(ctx.FormValue("map"), &uploadsMap); err != nil {
+	if !seenMap {
 		ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
 		writeJsonError(ctx, "map form field could not be decoded")
 		return
 	}
 
-	var upload graphql.Upload
 	for key, paths := range uploadsMap {
 		if len(paths) == 0 {
 			ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
@@ -98,103 +239,32 @@ func (f MultipartForm) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor)
 			return
 		}
 
-		header, err := ctx.FormFile(key)
-		if err != nil {
-			ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-			writeJsonErrorf(ctx, "failed to get key %s from form", key)
-			return
-		}
-		file, err := header.Open()
-		if err != nil {
+		file := form.files[key]
+		if file == nil {
 			ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
 			writeJsonErrorf(ctx, "failed to get key %s from form", key)
 			return
 		}
-		defer file.Close()
-
-		if len(paths) == 1 {
-			upload = graphql.Upload{
-				File:        file,
-				Size:        header.Size,
-				Filename:    header.Filename,
-				ContentType: header.Header.Get("Content-Type"),
+
+		for _, path := range paths {
+			r, err := form.open(file)
+			if err != nil {
+				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
+				writeJsonErrorf(ctx, "failed to open file for key %s", key)
+				return
 			}
 
-			if err := params.AddUpload(upload, key, paths[0]); err != nil {
+			upload := graphql.Upload{
+				File:        r,
+				Size:        file.size,
+				Filename:    file.filename,
+				ContentType: file.contentType,
+			}
+			if err := params.AddUpload(upload, key, path); err != nil {
 				ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
 				writeJsonGraphqlError(ctx, err)
 				return
 			}
-		} else {
-			if int64(ctx.Request.Header.ContentLength()) < f.maxMemory() {
-				fileBytes, err := ioutil.ReadAll(file)
-				if err != nil {
-					ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-					writeJsonErrorf(ctx, "failed to read file for key %s", key)
-					return
-				}
-				for _, path := range paths {
-					upload = graphql.Upload{
-						File:        &bytesReader{s: &fileBytes, i: 0, prevRune: -1},
-						Size:        header.Size,
-						Filename:    header.Filename,
-						ContentType: header.Header.Get("Content-Type"),
-					}
-
-					if err := params.AddUpload(upload, key, path); err != nil {
-						ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-						writeJsonGraphqlError(ctx, err)
-						return
-					}
-				}
-			} else {
-				tmpFile, err := ioutil.TempFile(os.TempDir(), "gqlgen-")
-				if err != nil {
-					ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-					writeJsonErrorf(ctx, "failed to create temp file for key %s", key)
-					return
-				}
-				tmpName := tmpFile.Name()
-				defer func() {
-					_ = os.Remove(tmpName)
-				}()
-				_, err = io.Copy(tmpFile, file)
-				if err != nil {
-					ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-					if err := tmpFile.Close(); err != nil {
-						writeJsonErrorf(ctx, "failed to copy to temp file and close temp file for key %s", key)
-						return
-					}
-					writeJsonErrorf(ctx, "failed to copy to temp file for key %s", key)
-					return
-				}
-				if err := tmpFile.Close(); err != nil {
-					ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-					writeJsonErrorf(ctx, "failed to close temp file for key %s", key)
-					return
-				}
-				for _, path := range paths {
-					pathTmpFile, err := os.Open(tmpName)
-					if err != nil {
-						ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-						writeJsonErrorf(ctx, "failed to open temp file for key %s", key)
-						return
-					}
-					defer pathTmpFile.Close()
-					upload = graphql.Upload{
-						File:        pathTmpFile,
-						Size:        header.Size,
-						Filename:    header.Filename,
-						ContentType: header.Header.Get("Content-Type"),
-					}
-
-					if err := params.AddUpload(upload, key, path); err != nil {
-						ctx.Response.Header.SetStatusCode(fasthttp.StatusUnprocessableEntity)
-						writeJsonGraphqlError(ctx, err)
-						return
-					}
-				}
-			}
 		}
 	}
 
@@ -203,13 +273,180 @@ func (f MultipartForm) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor)
 		End:   graphql.Now(),
 	}
 
-	rc, gerr := exec.CreateOperationContext(ctx, &params)
+	opCtx, cancel := operationContext(ctx)
+	rc, gerr := exec.CreateOperationContext(opCtx, &params)
 	if gerr != nil {
+		cancel()
 		resp := exec.DispatchError(graphql.WithOperationContext(ctx, rc), gerr)
-		ctx.Response.Header.SetStatusCode(statusFor(gerr))
+		ctx.Response.Header.SetStatusCode(statusFor(contentType, gerr))
 		writeJson(ctx, resp)
 		return
 	}
-	responses, c := exec.DispatchOperation(ctx, rc)
-	writeJson(ctx, responses(c))
+
+	responses, c := exec.DispatchOperation(opCtx, rc)
+	cleanup = nil
+	writeResponses(ctx, responses, c, func() {
+		cancel()
+		form.cleanup()
+	})
+}
+
+// multipartBody returns a reader over the request body, decompressing it on the fly when it is gzipped. The body is
+// read as it arrives when the request holds a body stream, and from the body fasthttp has buffered otherwise. The
+// reader must be closed, which releases the request.
+func multipartBody(ctx *fasthttp.RequestCtx) (io.ReadCloser, error) {
+	if len(ctx.Request.Header.MultipartFormBoundary()) == 0 {
+		return nil, fasthttp.ErrNoMultipartForm
+	}
+
+	var body io.ReadCloser
+	if ctx.Request.IsBodyStream() {
+		body = streamBody(&ctx.Request)
+	} else {
+		body = ioutil.NopCloser(bytes.NewReader(ctx.Request.Body()))
+	}
+
+	switch encoding := string(ctx.Request.Header.Peek("Content-Encoding")); encoding {
+	case "":
+		return body, nil
+	case "gzip":
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			_ = body.Close()
+			return nil, err
+		}
+		return readCloser{Reader: r, Closer: body}, nil
+	default:
+		_ = body.Close()
+		return nil, fmt.Errorf("unsupported Content-Encoding: %q", encoding)
+	}
+}
+
+// streamBody pipes the body stream of req, closing the reader waits until req isn't read anymore.
+func streamBody(req *fasthttp.Request) io.ReadCloser {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = pw.CloseWithError(req.BodyWriteTo(pw))
+	}()
+	return readCloser{Reader: pr, Closer: closerFunc(func() error {
+		// the rest of the stream is discarded when the form is rejected before the end
+		err := pr.Close()
+		<-done
+		return err
+	})}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// multipartUploads tracks the files of a request, so they can be removed once the operation has ended.
+type multipartUploads struct {
+	uploads UploadStore
+	files   map[string]*uploadedFile
+	readers []io.Closer
+}
+
+type uploadedFile struct {
+	stored      StoredUpload
+	size        int64
+	filename    string
+	contentType string
+}
+
+var (
+	errFileTooLarge = errors.New("file too large")
+	errBodyTooLarge = errors.New("request body too large")
+)
+
+func (u *multipartUploads) store(key string, part *multipart.Part, maxSize int64) error {
+	counter := &countingReader{r: part}
+	if maxSize > 0 {
+		counter.r = &limitedReader{r: part, n: maxSize, err: errFileTooLarge}
+	}
+
+	stored, err := u.uploads.Store(key, counter)
+	if err != nil {
+		if counter.err != nil {
+			return counter.err
+		}
+		return err
+	}
+
+	if u.files == nil {
+		u.files = map[string]*uploadedFile{}
+	}
+	u.files[key] = &uploadedFile{
+		stored:      stored,
+		size:        counter.n,
+		filename:    part.FileName(),
+		contentType: part.Header.Get("Content-Type"),
+	}
+	return nil
+}
+
+func (u *multipartUploads) open(file *uploadedFile) (io.Reader, error) {
+	r, err := file.stored.Open()
+	if err != nil {
+		return nil, err
+	}
+	u.readers = append(u.readers, r)
+	return r, nil
+}
+
+func (u *multipartUploads) cleanup() {
+	for _, r := range u.readers {
+		_ = r.Close()
+	}
+	for _, file := range u.files {
+		_ = file.stored.Remove()
+	}
+}
+
+// countingReader counts the bytes read from r and remembers the error that stopped it.
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// limitedReader reads at most n bytes from r, and fails with err past them.
+type limitedReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.n < 0 {
+		return 0, r.err
+	}
+	// read one byte past the limit to tell an input of exactly n bytes from a bigger one
+	if int64(len(p)) > r.n+1 {
+		p = p[:r.n+1]
+	}
+	n, err := r.r.Read(p)
+	r.n -= int64(n)
+	if r.n < 0 {
+		return n + int(r.n), r.err
+	}
+	return n, err
 }
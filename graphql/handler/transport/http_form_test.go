@@ -244,9 +244,135 @@ func TestFileUpload(t *testing.T) {
 	t.Run("fail parse request big body", func(t *testing.T) {
 		multipartForm.MaxUploadSize = 2
 		resp := upload(t, h.Handler(), validOperations, validMap, validFiles)
-		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, fasthttp.StatusRequestEntityTooLarge, resp.StatusCode(), string(resp.Body()))
 		require.Equal(t, `{"errors":[{"message":"failed to parse multipart form, request body too large"}],"data":null}`, string(resp.Body()))
 	})
+
+	t.Run("files are removed from the store when the operation ends", func(t *testing.T) {
+		multipartForm.MaxUploadSize = 0
+		store := &transport.MemoryUploadStore{}
+		multipartForm.Store = store
+		defer func() { multipartForm.Store = nil }()
+
+		es.ExecFunc = func(ctx context.Context) graphql.ResponseHandler {
+			require.Equal(t, 1, store.Len())
+			return graphql.OneShot(&graphql.Response{Data: []byte(`{"singleUpload":"test"}`)})
+		}
+
+		resp := upload(t, h.Handler(), validOperations, validMap, validFiles)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, 0, store.Len())
+
+		resp = upload(t, h.Handler(), validOperations, `{ "0": ["variables.file"], "1": ["variables.other"] }`, validFiles)
+		require.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, 0, store.Len())
+	})
+
+	t.Run("files are removed once the incremental payloads have been written", func(t *testing.T) {
+		store := &transport.MemoryUploadStore{}
+		multipartForm.Store = store
+		defer func() { multipartForm.Store = nil }()
+
+		es.ExecFunc = func(ctx context.Context) graphql.ResponseHandler {
+			hasNext, done := true, false
+			payloads := []*graphql.Response{
+				{Data: []byte(`{"singleUpload":"test"}`), HasNext: &hasNext},
+				{HasNext: &done},
+			}
+			return func(ctx context.Context) *graphql.Response {
+				if len(payloads) == 0 {
+					return nil
+				}
+				payload := payloads[0]
+				payloads = payloads[1:]
+				return payload
+			}
+		}
+
+		resp := upload(t, h.Handler(), validOperations, validMap, validFiles)
+		require.True(t, resp.IsBodyStream())
+		require.Equal(t, 1, store.Len())
+
+		require.Contains(t, string(resp.Body()), `{"data":{"singleUpload":"test"},"hasNext":true}`)
+		require.Equal(t, 0, store.Len())
+	})
+
+	t.Run("fail file too large", func(t *testing.T) {
+		multipartForm.MaxFileSize = 4
+		defer func() { multipartForm.MaxFileSize = 0 }()
+
+		resp := upload(t, h.Handler(), validOperations, validMap, validFiles)
+		require.Equal(t, fasthttp.StatusRequestEntityTooLarge, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"file for key 0 is larger than 4 bytes"}],"data":null}`, string(resp.Body()))
+
+		es.ExecFunc = func(ctx context.Context) graphql.ResponseHandler {
+			return graphql.OneShot(&graphql.Response{Data: []byte(`{"singleUpload":"test"}`)})
+		}
+		multipartForm.MaxFileSize = 5
+		resp = upload(t, h.Handler(), validOperations, validMap, validFiles)
+		require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+	})
+
+	t.Run("fail too many files", func(t *testing.T) {
+		multipartForm.MaxFiles = 1
+		defer func() { multipartForm.MaxFiles = 0 }()
+
+		operations := `{ "query": "mutation($files: [Upload!]!) { multipleUpload(files: $files) }", "variables": { "files": [null, null] } }`
+		mapData := `{ "0": ["variables.files.0"], "1": ["variables.files.1"] }`
+		files := []file{
+			{mapKey: "0", name: "a.txt", content: "test1", contentType: "text/plain"},
+			{mapKey: "1", name: "b.txt", content: "test2", contentType: "text/plain"},
+		}
+		resp := upload(t, h.Handler(), operations, mapData, files)
+		require.Equal(t, fasthttp.StatusRequestEntityTooLarge, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"too many files, at most 1 are allowed"}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("streamed request body", func(t *testing.T) {
+		es.ExecFunc = func(ctx context.Context) graphql.ResponseHandler {
+			upload := graphql.GetOperationContext(ctx).Variables["file"].(graphql.Upload)
+			b, err := ioutil.ReadAll(upload.File)
+			require.NoError(t, err)
+			require.Equal(t, "test1", string(b))
+			return graphql.OneShot(&graphql.Response{Data: []byte(`{"singleUpload":"test"}`)})
+		}
+
+		body, contentType := formBody(t, validOperations, validMap, validFiles)
+
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.Header.SetMethod("POST")
+		req.SetRequestURI("/graphql")
+		req.Header.SetContentType(contentType)
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+		// Init copies the body of req, not its stream
+		fctx.Request.SetBodyStream(body, -1)
+		h.Handler()(&fctx)
+
+		require.Equal(t, fasthttp.StatusOK, fctx.Response.StatusCode(), string(fctx.Response.Body()))
+		require.Equal(t, `{"data":{"singleUpload":"test"}}`, string(fctx.Response.Body()))
+	})
+
+	t.Run("content type allowlist", func(t *testing.T) {
+		multipartForm.AllowedContentTypes = []string{"image/*", "application/pdf"}
+		defer func() { multipartForm.AllowedContentTypes = nil }()
+
+		resp := upload(t, h.Handler(), validOperations, validMap, validFiles)
+		require.Equal(t, fasthttp.StatusUnsupportedMediaType, resp.StatusCode(), string(resp.Body()))
+		require.Equal(t, `{"errors":[{"message":"content type text/plain is not allowed for key 0"}],"data":null}`, string(resp.Body()))
+
+		es.ExecFunc = func(ctx context.Context) graphql.ResponseHandler {
+			return graphql.OneShot(&graphql.Response{Data: []byte(`{"singleUpload":"test"}`)})
+		}
+		for _, contentType := range []string{"image/png", "application/pdf"} {
+			files := []file{{mapKey: "0", name: "a", content: "test1", contentType: contentType}}
+			resp = upload(t, h.Handler(), validOperations, validMap, files)
+			require.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		}
+	})
 }
 
 type file struct {
@@ -257,6 +383,26 @@ type file struct {
 }
 
 func upload(t *testing.T, handler fasthttp.RequestHandler, operations, mapData string, files []file) *fasthttp.Response {
+	body, contentType := formBody(t, operations, mapData, files)
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	req.Header.SetMethod("POST")
+	req.SetRequestURI("/graphql")
+	req.SetBody(body.Bytes())
+	req.Header.SetContentLength(body.Len())
+	req.Header.SetContentType(contentType)
+
+	var fctx fasthttp.RequestCtx
+	fctx.Init(req, nil, nil)
+
+	handler(&fctx)
+
+	return &fctx.Response
+}
+
+func formBody(t *testing.T, operations, mapData string, files []file) (*bytes.Buffer, string) {
 	bodyBuf := &bytes.Buffer{}
 	bodyWriter := multipart.NewWriter(bodyBuf)
 
@@ -278,19 +424,5 @@ func upload(t *testing.T, handler fasthttp.RequestHandler, operations, mapData s
 	err = bodyWriter.Close()
 	require.NoError(t, err)
 
-	req := fasthttp.AcquireRequest()
-	defer fasthttp.ReleaseRequest(req)
-
-	req.Header.SetMethod("POST")
-	req.SetRequestURI("/graphql")
-	req.SetBody(bodyBuf.Bytes())
-	req.Header.SetContentLength(bodyBuf.Len())
-	req.Header.SetContentType(bodyWriter.FormDataContentType())
-
-	var fctx fasthttp.RequestCtx
-	fctx.Init(req, nil, nil)
-
-	handler(&fctx)
-
-	return &fctx.Response
+	return bodyBuf, bodyWriter.FormDataContentType()
 }
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"strings"
 
@@ -14,7 +15,21 @@ import (
 
 // GET implements the GET side of the default HTTP transport
 // defined in https://github.com/APIs-guru/graphql-over-http#get
-type GET struct{}
+//
+// Only queries can be sent over GET, other operations are answered with 405. Like POST, the response media type is
+// negotiated from the Accept header.
+type GET struct {
+	// CORS adds the Access-Control-* headers of the policy to responses, use the same policy as Options.
+	CORS *CORS
+
+	// Compression compresses large responses with an encoding the client accepts, they are sent uncompressed when
+	// nil. Share one policy between the transports.
+	Compression *Compression
+
+	// CSRF rejects the requests a browser could send cross-origin without a preflight, use the same policy as
+	// MultipartForm.
+	CSRF *CSRF
+}
 
 var _ graphql.Transport = GET{}
 
@@ -27,48 +42,68 @@ func (h GET) Supports(ctx *fasthttp.RequestCtx) bool {
 }
 
 func (h GET) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
-	ctx.Response.Header.SetContentType("application/json")
+	applyCORS(h.CORS, ctx)
+	defer compressResponse(h.Compression, ctx)
+
+	contentType, ok := negotiateContentType(ctx)
+	if !ok || !preventCSRF(h.CSRF, ctx) {
+		return
+	}
+
+	raw, err := readQueryParams(ctx)
+	if err != nil {
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+		writeJsonError(ctx, err.Error())
+		return
+	}
 
+	opCtx, cancel := operationContext(ctx)
+	rc, gerr := exec.CreateOperationContext(opCtx, raw)
+	if gerr != nil {
+		cancel()
+		ctx.Response.Header.SetStatusCode(statusFor(contentType, gerr))
+		resp := exec.DispatchError(graphql.WithOperationContext(ctx, rc), gerr)
+		writeJson(ctx, resp)
+		return
+	}
+	op := rc.Doc.Operations.ForName(rc.OperationName)
+	if op.Operation != ast.Query {
+		cancel()
+		ctx.Response.Header.Set("Allow", fasthttp.MethodPost)
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+		writeJsonError(ctx, "GET requests only allow query operations")
+		return
+	}
+
+	responses, c := exec.DispatchOperation(opCtx, rc)
+	writeResponses(ctx, responses, c, cancel)
+}
+
+// readQueryParams decodes the graphql request parameters from the url query string.
+func readQueryParams(ctx *fasthttp.RequestCtx) (*graphql.RawParams, error) {
 	raw := &graphql.RawParams{
 		Query:         string(ctx.QueryArgs().Peek("query")),
 		OperationName: string(ctx.QueryArgs().Peek("operationName")),
+		DocumentID:    string(ctx.QueryArgs().Peek("doc_id")),
+		ID:            string(ctx.QueryArgs().Peek("id")),
 	}
 	raw.ReadTime.Start = graphql.Now()
 
 	if variables := string(ctx.QueryArgs().Peek("variables")); variables != "" {
 		if err := jsonDecode(strings.NewReader(variables), &raw.Variables); err != nil {
-			ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
-			writeJsonError(ctx, "variables could not be decoded")
-			return
+			return nil, errors.New("variables could not be decoded")
 		}
 	}
 
 	if extensions := string(ctx.QueryArgs().Peek("extensions")); extensions != "" {
 		if err := jsonDecode(strings.NewReader(extensions), &raw.Extensions); err != nil {
-			ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
-			writeJsonError(ctx, "extensions could not be decoded")
-			return
+			return nil, errors.New("extensions could not be decoded")
 		}
 	}
 
 	raw.ReadTime.End = graphql.Now()
 
-	rc, err := exec.CreateOperationContext(ctx, raw)
-	if err != nil {
-		ctx.Response.Header.SetStatusCode(statusFor(err))
-		resp := exec.DispatchError(graphql.WithOperationContext(ctx, rc), err)
-		writeJson(ctx, resp)
-		return
-	}
-	op := rc.Doc.Operations.ForName(rc.OperationName)
-	if op.Operation != ast.Query {
-		ctx.Response.Header.SetStatusCode(fasthttp.StatusNotAcceptable)
-		writeJsonError(ctx, "GET requests only allow query operations")
-		return
-	}
-
-	responses, c := exec.DispatchOperation(ctx, rc)
-	writeJson(ctx, responses(c))
+	return raw, nil
 }
 
 func jsonDecode(r io.Reader, val interface{}) error {
@@ -77,9 +112,18 @@ func jsonDecode(r io.Reader, val interface{}) error {
 	return dec.Decode(val)
 }
 
-func statusFor(errs gqlerror.List) int {
-	switch errcode.GetErrorKind(errs) {
-	case errcode.KindProtocol:
+// statusFor returns the status code of a request that failed before it could be executed, from the kind of its
+// errors. Responses with the application/graphql-response+json media type answer all request errors with 400, as the
+// GraphQL over HTTP spec requires, application/json ones only use 422 for the protocol errors, like parse and
+// validation errors. Rate limited requests get a 429 whatever the media type.
+func statusFor(contentType string, errs gqlerror.List) int {
+	kind := errcode.GetErrorKind(errs)
+	switch {
+	case kind == errcode.KindRateLimited:
+		return fasthttp.StatusTooManyRequests
+	case contentType == contentTypeGraphQLResponseJSON:
+		return fasthttp.StatusBadRequest
+	case kind == errcode.KindProtocol:
 		return fasthttp.StatusUnprocessableEntity
 	default:
 		return fasthttp.StatusOK
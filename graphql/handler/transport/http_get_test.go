@@ -44,7 +44,8 @@ func TestGET(t *testing.T) {
 
 	t.Run("no mutations", func(t *testing.T) {
 		resp := doRequest(h.Handler(), "GET", "/graphql?query=mutation{name}", "")
-		assert.Equal(t, fasthttp.StatusNotAcceptable, resp.StatusCode(), string(resp.Body()))
+		assert.Equal(t, fasthttp.StatusMethodNotAllowed, resp.StatusCode(), string(resp.Body()))
+		assert.Equal(t, "POST", string(resp.Header.Peek("Allow")))
 		assert.Equal(t, `{"errors":[{"message":"GET requests only allow query operations"}],"data":null}`, string(resp.Body()))
 	})
 
@@ -2,15 +2,36 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"mime"
+	"sync"
 
 	"github.com/sujamess/fastgql/graphql"
 	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 // POST implements the POST side of the default HTTP transport
 // defined in https://github.com/APIs-guru/graphql-over-http#post
-type POST struct{}
+//
+// The response is application/graphql-response+json when the client asks for it in the Accept header, with the status
+// codes of the GraphQL over HTTP spec. Other clients get application/json.
+//
+// A JSON array of operations is executed as a batch, the responses are returned as a JSON array in the same order.
+//
+// Operations using @defer or @stream get a multipart/mixed response, with the initial payload and each incremental
+// payload in a part of its own.
+type POST struct {
+	// MaxBatchSize sets the maximum number of operations accepted in a single batched request. Defaults to 10.
+	MaxBatchSize int
+
+	// CORS adds the Access-Control-* headers of the policy to responses, use the same policy as Options.
+	CORS *CORS
+
+	// Compression compresses large responses with an encoding the client accepts, they are sent uncompressed when
+	// nil. Share one policy between the transports.
+	Compression *Compression
+}
 
 var _ graphql.Transport = POST{}
 
@@ -27,12 +48,31 @@ func (h POST) Supports(ctx *fasthttp.RequestCtx) bool {
 	return string(ctx.Method()) == "POST" && mediaType == "application/json"
 }
 
+func (h POST) maxBatchSize() int {
+	if h.MaxBatchSize == 0 {
+		return 10
+	}
+	return h.MaxBatchSize
+}
+
 func (h POST) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
-	ctx.Response.Header.SetContentType("application/json")
+	applyCORS(h.CORS, ctx)
+	defer compressResponse(h.Compression, ctx)
+
+	contentType, ok := negotiateContentType(ctx)
+	if !ok {
+		return
+	}
+
+	body := ctx.Request.Body()
+	if isBatch(body) {
+		h.doBatch(ctx, exec, body)
+		return
+	}
 
 	var params *graphql.RawParams
 	start := graphql.Now()
-	if err := jsonDecode(bytes.NewReader(ctx.Request.Body()), &params); err != nil {
+	if err := jsonDecode(bytes.NewReader(body), &params); err != nil {
 		ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
 		writeJsonErrorf(ctx, "json body could not be decoded: "+err.Error())
 		return
@@ -42,15 +82,103 @@ func (h POST) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
 		End:   graphql.Now(),
 	}
 
-	rc, err := exec.CreateOperationContext(ctx, params)
+	opCtx, cancel := operationContext(ctx)
+	rc, err := exec.CreateOperationContext(opCtx, params)
 	if err != nil {
-		ctx.Response.Header.SetStatusCode(statusFor(err))
+		cancel()
+		ctx.Response.Header.SetStatusCode(statusFor(contentType, err))
 		resp := exec.DispatchError(graphql.WithOperationContext(ctx, rc), err)
 		writeJson(ctx, resp)
 		return
 	}
 
-	graphql.WithOperationContext(ctx, rc)
+	responses, c := exec.DispatchOperation(opCtx, rc)
+	writeResponses(ctx, responses, c, cancel)
+}
+
+func (h POST) doBatch(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor, body []byte) {
+	var batch []*graphql.RawParams
+	start := graphql.Now()
+	if err := jsonDecode(bytes.NewReader(body), &batch); err != nil {
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+		writeJsonErrorf(ctx, "json body could not be decoded: "+err.Error())
+		return
+	}
+	end := graphql.Now()
+
+	if len(batch) == 0 {
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+		writeJsonError(ctx, "batch must contain at least one operation")
+		return
+	}
+
+	if len(batch) > h.maxBatchSize() {
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+		writeJsonErrorf(ctx, "batch of %d operations exceeds the limit of %d", len(batch), h.maxBatchSize())
+		return
+	}
+
+	// the operations of the batch are cancelled together
+	opCtx, cancel := operationContext(ctx)
+	defer cancel()
+
+	responses := make([]*graphql.Response, len(batch))
+	var wg sync.WaitGroup
+	for i, params := range batch {
+		if params == nil {
+			responses[i] = &graphql.Response{Errors: gqlerror.List{{Message: "operation must be an object"}}}
+			continue
+		}
+		params.ReadTime = graphql.TraceTiming{
+			Start: start,
+			End:   end,
+		}
+
+		wg.Add(1)
+		go func(i int, params *graphql.RawParams) {
+			defer wg.Done()
+			responses[i] = executeBatchedOperation(opCtx, exec, params)
+		}(i, params)
+	}
+	wg.Wait()
+
+	writeJsonBatch(ctx, responses)
+}
+
+// executeBatchedOperation runs a single operation of a batch. Every operation gets its own operation context, so
+// extensions see each of them as a separate request.
+func executeBatchedOperation(ctx context.Context, exec graphql.GraphExecutor, params *graphql.RawParams) (resp *graphql.Response) {
+	var rc *graphql.OperationContext
+	defer func() {
+		if r := recover(); r != nil {
+			var err error
+			if rc != nil {
+				err = rc.Recover(graphql.WithOperationContext(ctx, rc), r)
+			} else {
+				err = graphql.DefaultRecover(ctx, r)
+			}
+			resp = &graphql.Response{Errors: gqlerror.List{{Message: err.Error()}}}
+		}
+	}()
+
+	rc, err := exec.CreateOperationContext(ctx, params)
+	if err != nil {
+		return exec.DispatchError(graphql.WithOperationContext(ctx, rc), err)
+	}
+
 	responses, c := exec.DispatchOperation(ctx, rc)
-	writeJson(ctx, responses(c))
+	resp = responses(c)
+	if resp != nil && resp.HasNext != nil {
+		// a batch has no room for incremental payloads
+		go drainResponses(responses, c)
+		resp.HasNext = nil
+		resp.Errors = append(resp.Errors, &gqlerror.Error{Message: "@defer and @stream are not supported in batched operations"})
+	}
+	return resp
+}
+
+// isBatch reports whether the request body holds a JSON array of operations.
+func isBatch(body []byte) bool {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	return len(body) > 0 && body[0] == '['
 }
@@ -55,6 +55,28 @@ func TestPOST(t *testing.T) {
 		assert.Equal(t, `{"errors":[{"message":"mutations are not supported"}],"data":null}`, string(resp.Body()))
 	})
 
+	t.Run("batch", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `[{"query":"{ name }"},{"query": "!"},{"query":"mutation { name }"}]`)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode(), string(resp.Body()))
+		assert.Equal(t, string(resp.Header.ContentType()), "application/json")
+		assert.Equal(t, `[{"data":{"name":"test"}},{"errors":[{"message":"Unexpected !","locations":[{"line":1,"column":1}],"extensions":{"code":"GRAPHQL_PARSE_FAILED"}}],"data":null},{"errors":[{"message":"mutations are not supported"}],"data":null}]`, string(resp.Body()))
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", ` []`)
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode(), string(resp.Body()))
+		assert.Equal(t, `{"errors":[{"message":"batch must contain at least one operation"}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("batch too large", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.POST{MaxBatchSize: 1})
+
+		resp := doRequest(h.Handler(), "POST", "/graphql", `[{"query":"{ name }"},{"query":"{ name }"}]`)
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode(), string(resp.Body()))
+		assert.Equal(t, `{"errors":[{"message":"batch of 2 operations exceeds the limit of 1"}],"data":null}`, string(resp.Body()))
+	})
+
 	t.Run("validate content type", func(t *testing.T) {
 		doReq := func(handler fasthttp.RequestHandler, method string, target string, body string, contentType string) *fasthttp.Response {
 			req := fasthttp.AcquireRequest()
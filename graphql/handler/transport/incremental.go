@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/valyala/fasthttp"
+)
+
+// writeResponses writes the result of an operation. Operations using @defer or @stream are delivered as
+// multipart/mixed, with the initial payload and every incremental payload in a part of its own. cancel ends the
+// operation once its responses have been written, or received when the client has gone away, so it can release what
+// the payloads still use.
+func writeResponses(ctx *fasthttp.RequestCtx, responses graphql.ResponseHandler, c context.Context, cancel context.CancelFunc) {
+	streamed := false
+	defer func() {
+		// the incremental payloads are written once the handler returns
+		if !streamed {
+			cancel()
+		}
+	}()
+
+	initial := responses(c)
+	if initial == nil || initial.HasNext == nil || !*initial.HasNext {
+		writeJson(ctx, initial)
+		return
+	}
+
+	streamed = true
+
+	// the shutdown waits for the incremental payloads
+	release := holdShutdown(ctx)
+	ctx.Response.Header.SetContentType(`multipart/mixed; boundary="-"`)
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		for response := initial; response != nil; response = responses(c) {
+			fmt.Fprint(w, "\r\n---\r\nContent-Type: application/json; charset=utf-8\r\n\r\n")
+			writeJson(w, response)
+			if err := w.Flush(); err != nil {
+				// the client has gone away, the deferred results still need to be received to let them finish
+				go func() {
+					drainResponses(responses, c)
+					cancel()
+					release()
+				}()
+				return
+			}
+		}
+		cancel()
+		fmt.Fprint(w, "\r\n-----\r\n")
+		release()
+	})
+}
+
+func drainResponses(responses graphql.ResponseHandler, c context.Context) {
+	for responses(c) != nil {
+	}
+}
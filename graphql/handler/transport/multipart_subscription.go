@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// MultipartSubscription implements the multipart HTTP subscription protocol of Apollo Client and Router
+// https://www.apollographql.com/docs/router/executing-operations/subscription-multipart-protocol
+//
+// Every response of the operation is streamed as a part of a multipart/mixed response, wrapped in a payload envelope.
+// Errors ending the operation outside of its results, like a subscription ended by an error, are sent in the errors
+// of the envelope with a null payload. MultipartSubscription must be added to the server before the POST transport,
+// as it would otherwise claim its requests.
+type MultipartSubscription struct {
+	// HeartbeatInterval sets how often an empty part is sent to keep idle connections open and to detect clients
+	// that went away. Defaults to 5 seconds.
+	HeartbeatInterval time.Duration
+
+	// CORS adds the Access-Control-* headers of the policy to responses, use the same policy as Options.
+	CORS *CORS
+}
+
+var _ graphql.Transport = MultipartSubscription{}
+
+// multipartEnvelope wraps the responses sent by a multipart subscription, the payload is null when it carries a
+// transport error.
+type multipartEnvelope struct {
+	Payload *graphql.Response `json:"payload"`
+	Errors  gqlerror.List     `json:"errors,omitempty"`
+}
+
+// multipartHeartbeat is the empty part keeping the connection alive.
+var multipartHeartbeat = struct{}{}
+
+const multipartBoundary = "graphql"
+
+func (t MultipartSubscription) Supports(ctx *fasthttp.RequestCtx) bool {
+	if string(ctx.Request.Header.Peek("Upgrade")) != "" || !ctx.IsPost() {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(string(ctx.Request.Header.ContentType()))
+	if err != nil || mediaType != "application/json" {
+		return false
+	}
+
+	for _, accepted := range strings.Split(string(ctx.Request.Header.Peek("Accept")), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == "multipart/mixed" && params["subscriptionspec"] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (t MultipartSubscription) heartbeatInterval() time.Duration {
+	if t.HeartbeatInterval == 0 {
+		return 5 * time.Second
+	}
+	return t.HeartbeatInterval
+}
+
+func (t MultipartSubscription) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
+	applyCORS(t.CORS, ctx)
+
+	ctx.Response.Header.SetContentType(`multipart/mixed;boundary="` + multipartBoundary + `";subscriptionSpec="1.0"`)
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	ctx.Response.Header.Set("X-Accel-Buffering", "no")
+
+	var params *graphql.RawParams
+	start := graphql.Now()
+	if err := jsonDecode(bytes.NewReader(ctx.Request.Body()), &params); err != nil {
+		envelope := &multipartEnvelope{Errors: gqlerror.List{{Message: "json body could not be decoded: " + err.Error()}}}
+		ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			_ = writeMultipartPart(w, envelope)
+			fmt.Fprint(w, "\r\n--"+multipartBoundary+"--\r\n")
+		})
+		return
+	}
+	params.ReadTime = graphql.TraceTiming{
+		Start: start,
+		End:   graphql.Now(),
+	}
+
+	rc, gerr := exec.CreateOperationContext(ctx, params)
+	if gerr != nil {
+		resp := exec.DispatchError(graphql.WithOperationContext(ctx, rc), gerr)
+		ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			_ = writeMultipartPart(w, &multipartEnvelope{Payload: resp})
+			fmt.Fprint(w, "\r\n--"+multipartBoundary+"--\r\n")
+		})
+		return
+	}
+
+	// the stream is written once the request context has been recycled, the shutdown and the request are picked up
+	// beforehand, and the shutdown waits for the stream to end
+	shuttingDown := graphql.ShuttingDown(ctx)
+	request := graphql.CopyRequestInfo(ctx)
+	release := holdShutdown(ctx)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer release()
+		opCtx, cancel := context.WithCancel(graphql.WithStreaming(graphql.WithRequestInfo(ctx, request)))
+		defer cancel()
+
+		responses, opCtx := exec.DispatchOperation(opCtx, rc)
+
+		next := make(chan *graphql.Response)
+		go func() {
+			defer close(next)
+			for {
+				response := responses(opCtx)
+				if response == nil {
+					return
+				}
+
+				select {
+				case next <- response:
+				case <-opCtx.Done():
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(t.heartbeatInterval())
+		defer heartbeat.Stop()
+
+		// stopping the operation when the server shuts down closes next, which ends the stream
+		shutdown := shuttingDown
+
+		// an initial heartbeat lets the client know the stream is open before the first result is ready
+		if err := writeMultipartPart(w, multipartHeartbeat); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case response, ok := <-next:
+				if !ok {
+					fmt.Fprint(w, "\r\n--"+multipartBoundary+"--\r\n")
+					_ = w.Flush()
+					return
+				}
+
+				// a subscription ended by an error is a transport error for the protocol, the stream ends with it
+				if response.Fatal {
+					_ = writeMultipartPart(w, &multipartEnvelope{Errors: response.Errors})
+					fmt.Fprint(w, "\r\n--"+multipartBoundary+"--\r\n")
+					_ = w.Flush()
+					return
+				}
+				if err := writeMultipartPart(w, &multipartEnvelope{Payload: response}); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				// the client has gone away, the deferred cancel stops the operation
+				if err := writeMultipartPart(w, multipartHeartbeat); err != nil {
+					return
+				}
+			case <-shutdown:
+				shutdown = nil
+				cancel()
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+func writeMultipartPart(w *bufio.Writer, part interface{}) error {
+	b, err := json.Marshal(part)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(w, "\r\n--%s\r\nContent-Type: application/json\r\n\r\n%s", multipartBoundary, b)
+	return w.Flush()
+}
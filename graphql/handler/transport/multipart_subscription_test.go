@@ -0,0 +1,177 @@
+package transport_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const multipartAccept = `multipart/mixed;subscriptionSpec="1.0", application/json`
+
+func TestMultipartSubscription(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.MultipartSubscription{})
+	h.AddTransport(transport.POST{})
+
+	doMultipartRequest := func(body string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI("/graphql")
+		req.Header.SetMethod("POST")
+		req.Header.SetContentType("application/json")
+		req.Header.Set("Accept", multipartAccept)
+		req.SetBody([]byte(body))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		h.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	t.Run("only claims multipart subscription requests", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ name }"}`)
+		assert.Equal(t, "application/json", string(resp.Header.ContentType()))
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		resp := doMultipartRequest(`{"query":"{ name }"}`)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, `multipart/mixed;boundary="graphql";subscriptionSpec="1.0"`, string(resp.Header.ContentType()))
+		assert.Equal(t, "\r\n--graphql\r\nContent-Type: application/json\r\n\r\n{}"+
+			"\r\n--graphql\r\nContent-Type: application/json\r\n\r\n{\"payload\":{\"data\":{\"name\":\"test\"}}}"+
+			"\r\n--graphql--\r\n", string(resp.Body()))
+	})
+
+	t.Run("decode failure", func(t *testing.T) {
+		resp := doMultipartRequest("notjson")
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, "\r\n--graphql\r\nContent-Type: application/json\r\n\r\n"+
+			`{"payload":null,"errors":[{"message":"json body could not be decoded: invalid character 'o' in literal null (expecting 'u')"}]}`+
+			"\r\n--graphql--\r\n", string(resp.Body()))
+	})
+
+	t.Run("parse failure", func(t *testing.T) {
+		resp := doMultipartRequest(`{"query": "!"}`)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, "\r\n--graphql\r\nContent-Type: application/json\r\n\r\n"+
+			`{"payload":{"errors":[{"message":"Unexpected !","locations":[{"line":1,"column":1}],"extensions":{"code":"GRAPHQL_PARSE_FAILED"}}],"data":null}}`+
+			"\r\n--graphql--\r\n", string(resp.Body()))
+	})
+
+	t.Run("a subscription ended by an error", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.MultipartSubscription{})
+		h.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+			resp := next(ctx)
+			if resp != nil {
+				resp.Errors = gqlerror.List{{Message: "the feed is gone"}}
+				resp.Fatal = true
+			}
+			return resp
+		})
+		go h.SendNextSubscriptionMessage()
+
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		req.SetRequestURI("/graphql")
+		req.Header.SetMethod("POST")
+		req.Header.SetContentType("application/json")
+		req.Header.Set("Accept", multipartAccept)
+		req.SetBody([]byte(`{"query":"subscription { name }"}`))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+		h.Handler()(&fctx)
+
+		assert.Equal(t, "\r\n--graphql\r\nContent-Type: application/json\r\n\r\n{}"+
+			"\r\n--graphql\r\nContent-Type: application/json\r\n\r\n"+`{"payload":null,"errors":[{"message":"the feed is gone"}]}`+
+			"\r\n--graphql--\r\n", string(fctx.Response.Body()))
+	})
+}
+
+func TestMultipartSubscriptionStream(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.MultipartSubscription{HeartbeatInterval: 50 * time.Millisecond})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	req, err := http.NewRequest("POST", "http://"+ln.Addr().String()+"/graphql", strings.NewReader(`{"query":"subscription { name }"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", multipartAccept)
+	// the server is shut down at the end, its connection mustn't be reused
+	req.Close = true
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, `multipart/mixed;boundary="graphql";subscriptionSpec="1.0"`, resp.Header.Get("Content-Type"))
+
+	// readPart returns the body of the next part, or the closing delimiter
+	r := bufio.NewReader(resp.Body)
+	readPart := func() string {
+		delimiter, err := r.ReadString('\n')
+		require.NoError(t, err)
+		if delimiter == "\r\n" {
+			delimiter, err = r.ReadString('\n')
+			require.NoError(t, err)
+		}
+		if delimiter == "--graphql--\r\n" {
+			return delimiter
+		}
+		require.Equal(t, "--graphql\r\n", delimiter)
+
+		for {
+			header, err := r.ReadString('\n')
+			require.NoError(t, err)
+			if header == "\r\n" {
+				break
+			}
+		}
+
+		body, err := r.ReadString('\r')
+		require.NoError(t, err)
+		require.NoError(t, r.UnreadByte())
+		return strings.TrimSuffix(body, "\r")
+	}
+	// readResult skips the heartbeats sent while the result was on its way
+	readResult := func() string {
+		part := readPart()
+		for part == `{}` {
+			part = readPart()
+		}
+		return part
+	}
+
+	assert.Equal(t, `{}`, readPart())
+
+	h.SendNextSubscriptionMessage()
+	assert.Equal(t, `{"payload":{"data":{"name":"test"}}}`, readResult())
+
+	// heartbeats keep the stream alive while there is nothing to send
+	assert.Equal(t, `{}`, readPart())
+
+	h.SendNextSubscriptionMessage()
+	assert.Equal(t, `{"payload":{"data":{"name":"test"}}}`, readResult())
+
+	// shutting down the server ends the stream
+	require.NoError(t, h.Shutdown(context.Background()))
+	assert.Equal(t, "--graphql--\r\n", readResult())
+}
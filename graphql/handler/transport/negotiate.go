@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	contentTypeJSON                = "application/json"
+	contentTypeGraphQLResponseJSON = "application/graphql-response+json"
+)
+
+// negotiateContentType picks the media type of the response from the Accept header and sets it on the response,
+// following https://graphql.github.io/graphql-over-http/draft/#sec-Accept
+//
+// Clients that don't send an Accept header, or accept anything, keep getting application/json with its legacy status
+// codes. Only clients asking for application/graphql-response+json opt into the status codes of the spec. When the
+// client accepts neither, the request is answered with 406 and ok is false.
+func negotiateContentType(ctx *fasthttp.RequestCtx) (contentType string, ok bool) {
+	ctx.Response.Header.SetContentType(contentTypeJSON)
+
+	accept := strings.TrimSpace(string(ctx.Request.Header.Peek("Accept")))
+	if accept == "" {
+		return contentTypeJSON, true
+	}
+
+	var best string
+	var bestQuality float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		var candidate string
+		switch mediaType {
+		case contentTypeGraphQLResponseJSON:
+			candidate = contentTypeGraphQLResponseJSON
+		case contentTypeJSON, "application/*", "*/*":
+			candidate = contentTypeJSON
+		default:
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		// the first media type wins among the ones with the same quality
+		if quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+
+	if best == "" {
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusNotAcceptable)
+		writeJsonErrorf(ctx, "the Accept header must allow %s or %s", contentTypeGraphQLResponseJSON, contentTypeJSON)
+		return "", false
+	}
+
+	ctx.Response.Header.SetContentType(best)
+	return best, true
+}
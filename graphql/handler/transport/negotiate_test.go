@@ -0,0 +1,133 @@
+package transport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/errcode"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.GET{})
+	h.AddTransport(transport.POST{})
+	h.AddTransport(transport.MultipartForm{})
+
+	doAccept := func(method string, target string, body string, contentType string, accept string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI(target)
+		req.Header.SetMethod(method)
+		req.Header.SetContentType(contentType)
+		req.Header.Set("Accept", accept)
+		req.SetBody([]byte(body))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		h.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	t.Run("picks the accepted media type", func(t *testing.T) {
+		for accept, contentType := range map[string]string{
+			"application/graphql-response+json": "application/graphql-response+json",
+			"application/json":                  "application/json",
+			"*/*":                               "application/json",
+			"application/json;q=0.9, application/graphql-response+json": "application/graphql-response+json",
+			"application/graphql-response+json;q=0.5, application/*":    "application/json",
+			"text/html, application/graphql-response+json;q=0.8":        "application/graphql-response+json",
+		} {
+			resp := doAccept("POST", "/graphql", `{"query":"{ name }"}`, "application/json", accept)
+			assert.Equal(t, fasthttp.StatusOK, resp.StatusCode(), accept)
+			assert.Equal(t, contentType, string(resp.Header.ContentType()), accept)
+			assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+		}
+	})
+
+	t.Run("unsupported accept header", func(t *testing.T) {
+		for _, method := range []string{"GET", "POST"} {
+			resp := doAccept(method, "/graphql?query={name}", `{"query":"{ name }"}`, "application/json", "text/html, application/json;q=0")
+			assert.Equal(t, fasthttp.StatusNotAcceptable, resp.StatusCode(), method)
+			assert.Equal(t, "application/json", string(resp.Header.ContentType()))
+			assert.Equal(t, `{"errors":[{"message":"the Accept header must allow application/graphql-response+json or application/json"}],"data":null}`, string(resp.Body()))
+		}
+
+		resp := doAccept("POST", "/graphql", "", "multipart/form-data; boundary=X", "text/html")
+		assert.Equal(t, fasthttp.StatusNotAcceptable, resp.StatusCode())
+	})
+
+	t.Run("request errors are bad requests", func(t *testing.T) {
+		resp := doAccept("POST", "/graphql", `{"query": "!"}`, "application/json", "application/graphql-response+json")
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode())
+		assert.Equal(t, `{"errors":[{"message":"Unexpected !","locations":[{"line":1,"column":1}],"extensions":{"code":"GRAPHQL_PARSE_FAILED"}}],"data":null}`, string(resp.Body()))
+
+		resp = doAccept("GET", "/graphql?query={unknown}", "", "", "application/graphql-response+json")
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode())
+		assert.Equal(t, "application/graphql-response+json", string(resp.Header.ContentType()))
+	})
+
+	t.Run("legacy status codes for application/json", func(t *testing.T) {
+		resp := doAccept("POST", "/graphql", `{"query": "!"}`, "application/json", "application/json")
+		assert.Equal(t, fasthttp.StatusUnprocessableEntity, resp.StatusCode())
+	})
+
+	t.Run("status codes follow the kind of the errors", func(t *testing.T) {
+		errcode.RegisterErrorType("TEST_RATE_LIMITED", errcode.KindRateLimited)
+
+		for code, statuses := range map[string][2]int{
+			"":                       {fasthttp.StatusBadRequest, fasthttp.StatusOK},
+			errcode.ValidationFailed: {fasthttp.StatusBadRequest, fasthttp.StatusUnprocessableEntity},
+			"TEST_RATE_LIMITED":      {fasthttp.StatusTooManyRequests, fasthttp.StatusTooManyRequests},
+		} {
+			h := testserver.New()
+			h.AddTransport(transport.POST{})
+			h.Use(rejectOperation{code: code})
+
+			for i, accept := range []string{"application/graphql-response+json", "application/json"} {
+				req := fasthttp.AcquireRequest()
+				req.SetRequestURI("/graphql")
+				req.Header.SetMethod("POST")
+				req.Header.SetContentType("application/json")
+				req.Header.Set("Accept", accept)
+				req.SetBody([]byte(`{"query": "{ name }"}`))
+
+				var fctx fasthttp.RequestCtx
+				fctx.Init(req, nil, nil)
+				fasthttp.ReleaseRequest(req)
+				h.Handler()(&fctx)
+
+				assert.Equal(t, statuses[i], fctx.Response.StatusCode(), "%s with %s", code, accept)
+			}
+		}
+	})
+}
+
+// rejectOperation fails every operation before it runs with an error of code.
+type rejectOperation struct {
+	code string
+}
+
+func (r rejectOperation) ExtensionName() string {
+	return "RejectOperation"
+}
+
+func (r rejectOperation) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (r rejectOperation) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	err := gqlerror.Errorf("operation rejected")
+	if r.code != "" {
+		errcode.Set(err, r.code)
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/valyala/fasthttp"
+)
+
+// disconnectPollInterval sets how often the connection of a request is checked while its operation runs.
+const disconnectPollInterval = 250 * time.Millisecond
+
+// operationContext derives the context the operation of a POST or GET request runs in, the request context is only
+// done once the whole fasthttp server stops. It is cancelled when the client disconnects, when the shutdown of the
+// server gives up waiting for the requests in flight, or once the MaxOperationDuration of the server has passed.
+// cancel must be called once the operation is over.
+func operationContext(ctx *fasthttp.RequestCtx) (context.Context, context.CancelFunc) {
+	var opCtx context.Context
+	var cancel context.CancelFunc
+	if d := graphql.GetMaxOperationDuration(ctx); d > 0 {
+		opCtx, cancel = context.WithTimeout(ctx, d)
+	} else {
+		opCtx, cancel = context.WithCancel(ctx)
+	}
+
+	var aborted <-chan struct{}
+	if s := graphql.GetShutdown(ctx); s != nil {
+		aborted = s.Aborted()
+	}
+
+	conn := ctx.Conn()
+	_, watchConn := clientGone(conn)
+
+	go func() {
+		var poll <-chan time.Time
+		if watchConn {
+			ticker := time.NewTicker(disconnectPollInterval)
+			defer ticker.Stop()
+			poll = ticker.C
+		}
+
+		for {
+			select {
+			case <-opCtx.Done():
+				return
+			case <-aborted:
+				cancel()
+				return
+			case <-poll:
+				if gone, _ := clientGone(conn); gone {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return opCtx, cancel
+}
+
+// holdShutdown keeps the shutdown of the server serving ctx waiting for a response streamed once the handler has
+// returned, the stream writer must call release when it is done.
+func holdShutdown(ctx *fasthttp.RequestCtx) (release func()) {
+	s := graphql.GetShutdown(ctx)
+	if s == nil {
+		return func() {}
+	}
+	s.Hold()
+	return s.Release
+}
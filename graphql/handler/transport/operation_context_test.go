@@ -0,0 +1,54 @@
+package transport_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+)
+
+func TestOperationCancelledOnDisconnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("disconnected clients aren't detected on windows")
+	}
+
+	h := testserver.New()
+	h.AddTransport(transport.POST{})
+
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	h.AroundFields(func(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			cancelled <- ctx.Err()
+		case <-time.After(5 * time.Second):
+			cancelled <- nil
+		}
+		return next(ctx)
+	})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+
+	body := `{"query":"{ name }"}`
+	_, err = fmt.Fprintf(conn, "POST /graphql HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\n"+
+		"Content-Length: %d\r\n\r\n%s", len(body), body)
+	require.NoError(t, err)
+
+	<-started
+	require.NoError(t, conn.Close())
+
+	assert.Equal(t, context.Canceled, <-cancelled)
+}
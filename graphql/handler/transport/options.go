@@ -8,7 +8,11 @@ import (
 )
 
 // Options responds to http OPTIONS and HEAD requests
-type Options struct{}
+type Options struct {
+	// CORS answers preflight requests when set. Give the same policy to the other HTTP transports, so actual
+	// responses carry the matching headers.
+	CORS *CORS
+}
 
 var _ graphql.Transport = Options{}
 
@@ -20,9 +24,18 @@ func (o Options) Supports(ctx *fasthttp.RequestCtx) bool {
 func (o Options) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
 	switch string(ctx.Method()) {
 	case fasthttp.MethodOptions:
+		if o.CORS != nil && isPreflight(ctx) {
+			o.CORS.preflight(ctx)
+			return
+		}
 		ctx.Response.Header.SetStatusCode(fasthttp.StatusOK)
 		ctx.Response.Header.Set("Allow", "OPTIONS, GET, POST")
 	case http.MethodHead:
 		ctx.Response.Header.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+		ctx.Response.Header.Set("Allow", "OPTIONS, GET, POST")
 	}
 }
+
+func isPreflight(ctx *fasthttp.RequestCtx) bool {
+	return len(ctx.Request.Header.Peek("Origin")) > 0 && len(ctx.Request.Header.Peek("Access-Control-Request-Method")) > 0
+}
@@ -22,5 +22,6 @@ func TestOptions(t *testing.T) {
 	t.Run("responds to head requests", func(t *testing.T) {
 		resp := doRequest(h.Handler(), "HEAD", "/graphql?query={me{name}}", ``)
 		assert.Equal(t, fasthttp.StatusMethodNotAllowed, resp.StatusCode())
+		assert.Equal(t, "OPTIONS, GET, POST", string(resp.Header.Peek("Allow")))
 	})
 }
@@ -0,0 +1,196 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SSE implements the distinct connections mode of the GraphQL over Server-Sent Events protocol
+// https://github.com/enisdenjo/graphql-sse/blob/master/PROTOCOL.md
+//
+// Every response of the operation is streamed as a "next" event, followed by a single "complete" event. SSE must
+// be added to the server before the GET and POST transports, as they would otherwise claim its requests.
+type SSE struct {
+	// HeartbeatInterval sets how often a comment is sent to keep idle connections open and to detect clients
+	// that went away. Defaults to 15 seconds.
+	HeartbeatInterval time.Duration
+
+	// CORS adds the Access-Control-* headers of the policy to responses, use the same policy as Options.
+	CORS *CORS
+}
+
+var _ graphql.Transport = SSE{}
+
+func (t SSE) Supports(ctx *fasthttp.RequestCtx) bool {
+	if string(ctx.Request.Header.Peek("Upgrade")) != "" {
+		return false
+	}
+
+	if !strings.Contains(string(ctx.Request.Header.Peek("Accept")), "text/event-stream") {
+		return false
+	}
+
+	switch string(ctx.Method()) {
+	case fasthttp.MethodGet:
+		return true
+	case fasthttp.MethodPost:
+		mediaType, _, err := mime.ParseMediaType(string(ctx.Request.Header.ContentType()))
+		if err != nil {
+			return false
+		}
+		return mediaType == "application/json"
+	default:
+		return false
+	}
+}
+
+func (t SSE) heartbeatInterval() time.Duration {
+	if t.HeartbeatInterval == 0 {
+		return 15 * time.Second
+	}
+	return t.HeartbeatInterval
+}
+
+func (t SSE) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
+	applyCORS(t.CORS, ctx)
+
+	ctx.Response.Header.SetContentType("application/json")
+
+	var params *graphql.RawParams
+	if ctx.IsGet() {
+		var err error
+		if params, err = readQueryParams(ctx); err != nil {
+			ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+			writeJsonError(ctx, err.Error())
+			return
+		}
+	} else {
+		start := graphql.Now()
+		if err := jsonDecode(bytes.NewReader(ctx.Request.Body()), &params); err != nil {
+			ctx.Response.Header.SetStatusCode(fasthttp.StatusBadRequest)
+			writeJsonErrorf(ctx, "json body could not be decoded: "+err.Error())
+			return
+		}
+		params.ReadTime = graphql.TraceTiming{
+			Start: start,
+			End:   graphql.Now(),
+		}
+	}
+
+	rc, gerr := exec.CreateOperationContext(ctx, params)
+	if gerr == nil && ctx.IsGet() && rc.Operation.Operation == ast.Mutation {
+		ctx.Response.Header.Set("Allow", fasthttp.MethodPost)
+		ctx.Response.Header.SetStatusCode(fasthttp.StatusMethodNotAllowed)
+		writeJsonError(ctx, "GET requests only allow query and subscription operations")
+		return
+	}
+
+	ctx.Response.Header.SetContentType("text/event-stream")
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	ctx.Response.Header.Set("X-Accel-Buffering", "no")
+
+	if gerr != nil {
+		resp := exec.DispatchError(graphql.WithOperationContext(ctx, rc), gerr)
+		ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			_ = writeJsonWithSSE(w, resp)
+			fmt.Fprint(w, "event: complete\n\n")
+		})
+		return
+	}
+
+	// the stream is written once the request context has been recycled, the shutdown and the request are picked up
+	// beforehand, and the shutdown waits for the stream to end
+	shuttingDown := graphql.ShuttingDown(ctx)
+	request := graphql.CopyRequestInfo(ctx)
+	release := holdShutdown(ctx)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer release()
+		opCtx, cancel := context.WithCancel(graphql.WithStreaming(graphql.WithRequestInfo(ctx, request)))
+		defer cancel()
+
+		responses, opCtx := exec.DispatchOperation(opCtx, rc)
+
+		next := make(chan *graphql.Response)
+		go func() {
+			defer close(next)
+			for {
+				response := responses(opCtx)
+				if response == nil {
+					return
+				}
+
+				select {
+				case next <- response:
+				case <-opCtx.Done():
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(t.heartbeatInterval())
+		defer heartbeat.Stop()
+
+		// stopping the operation when the server shuts down closes next, which completes the stream
+		shutdown := shuttingDown
+
+		// an initial comment lets the client know the stream is open before the first result is ready
+		fmt.Fprint(w, ":\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case response, ok := <-next:
+				if !ok {
+					fmt.Fprint(w, "event: complete\n\n")
+					_ = w.Flush()
+					return
+				}
+
+				// a subscription ended by an error completes the stream right after its errors
+				if response.Fatal {
+					if err := writeJsonWithSSE(w, &graphql.Response{Errors: response.Errors}); err != nil {
+						return
+					}
+					fmt.Fprint(w, "event: complete\n\n")
+					_ = w.Flush()
+					return
+				}
+				if err := writeJsonWithSSE(w, response); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				fmt.Fprint(w, ":\n\n")
+				// the client has gone away, the deferred cancel stops the operation
+				if err := w.Flush(); err != nil {
+					return
+				}
+			case <-shutdown:
+				shutdown = nil
+				cancel()
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+func writeJsonWithSSE(w *bufio.Writer, response *graphql.Response) error {
+	b, err := json.Marshal(response)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(w, "event: next\ndata: %s\n\n", b)
+	return w.Flush()
+}
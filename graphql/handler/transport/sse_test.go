@@ -0,0 +1,164 @@
+package transport_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql"
+	"github.com/sujamess/fastgql/graphql/handler/testserver"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestSSE(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.SSE{})
+	h.AddTransport(transport.POST{})
+
+	doSSERequest := func(method string, target string, body string) *fasthttp.Response {
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+
+		req.SetRequestURI(target)
+		req.Header.SetMethod(method)
+		req.Header.SetContentType("application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		req.SetBody([]byte(body))
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, nil, nil)
+
+		h.Handler()(&fctx)
+
+		return &fctx.Response
+	}
+
+	t.Run("only claims event stream requests", func(t *testing.T) {
+		resp := doRequest(h.Handler(), "POST", "/graphql", `{"query":"{ name }"}`)
+		assert.Equal(t, "application/json", string(resp.Header.ContentType()))
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(resp.Body()))
+	})
+
+	t.Run("stream query over POST", func(t *testing.T) {
+		resp := doSSERequest("POST", "/graphql", `{"query":"{ name }"}`)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, "text/event-stream", string(resp.Header.ContentType()))
+		assert.Equal(t, ":\n\nevent: next\ndata: {\"data\":{\"name\":\"test\"}}\n\nevent: complete\n\n", string(resp.Body()))
+	})
+
+	t.Run("stream query over GET", func(t *testing.T) {
+		resp := doSSERequest("GET", "/graphql?query={name}", "")
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, ":\n\nevent: next\ndata: {\"data\":{\"name\":\"test\"}}\n\nevent: complete\n\n", string(resp.Body()))
+	})
+
+	t.Run("decode failure", func(t *testing.T) {
+		resp := doSSERequest("POST", "/graphql", "notjson")
+		assert.Equal(t, fasthttp.StatusBadRequest, resp.StatusCode())
+		assert.Equal(t, "application/json", string(resp.Header.ContentType()))
+		assert.Equal(t, `{"errors":[{"message":"json body could not be decoded: invalid character 'o' in literal null (expecting 'u')"}],"data":null}`, string(resp.Body()))
+	})
+
+	t.Run("parse failure", func(t *testing.T) {
+		resp := doSSERequest("POST", "/graphql", `{"query": "!"}`)
+		assert.Equal(t, fasthttp.StatusOK, resp.StatusCode())
+		assert.Equal(t, "event: next\ndata: {\"errors\":[{\"message\":\"Unexpected !\",\"locations\":[{\"line\":1,\"column\":1}],\"extensions\":{\"code\":\"GRAPHQL_PARSE_FAILED\"}}],\"data\":null}\n\nevent: complete\n\n", string(resp.Body()))
+	})
+
+	t.Run("no mutations over GET", func(t *testing.T) {
+		resp := doSSERequest("GET", "/graphql?query=mutation{name}", "")
+		assert.Equal(t, fasthttp.StatusMethodNotAllowed, resp.StatusCode())
+		assert.Equal(t, "POST", string(resp.Header.Peek("Allow")))
+		assert.Equal(t, `{"errors":[{"message":"GET requests only allow query and subscription operations"}],"data":null}`, string(resp.Body()))
+	})
+}
+
+func TestSSESubscription(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.SSE{HeartbeatInterval: 50 * time.Millisecond})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	req, err := http.NewRequest("POST", "http://"+ln.Addr().String()+"/graphql", strings.NewReader(`{"query":"subscription { name }"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	// the server is shut down at the end, its connection mustn't be reused
+	req.Close = true
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var lines []string
+		for {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	assert.Equal(t, ":\n", readEvent())
+
+	h.SendNextSubscriptionMessage()
+	assert.Equal(t, "event: next\ndata: {\"data\":{\"name\":\"test\"}}\n", readEvent())
+
+	// heartbeats keep the stream alive while there is nothing to send
+	assert.Equal(t, ":\n", readEvent())
+
+	h.SendNextSubscriptionMessage()
+	assert.Equal(t, "event: next\ndata: {\"data\":{\"name\":\"test\"}}\n", readEvent())
+
+	// shutting down the server completes the subscription
+	require.NoError(t, h.Shutdown(context.Background()))
+	event := readEvent()
+	for event == ":\n" {
+		event = readEvent()
+	}
+	assert.Equal(t, "event: complete\n", event)
+}
+
+func TestSSESubscriptionFatal(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.SSE{})
+	h.AroundResponses(func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		resp := next(ctx)
+		if resp != nil {
+			resp.Errors = gqlerror.List{{Message: "the feed is gone"}}
+			resp.Fatal = true
+		}
+		return resp
+	})
+	go h.SendNextSubscriptionMessage()
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI("/graphql")
+	req.Header.SetMethod("POST")
+	req.Header.SetContentType("application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	req.SetBody([]byte(`{"query":"subscription { name }"}`))
+
+	var fctx fasthttp.RequestCtx
+	fctx.Init(req, nil, nil)
+	h.Handler()(&fctx)
+
+	assert.Equal(t, ":\n\n"+
+		"event: next\ndata: {\"errors\":[{\"message\":\"the feed is gone\"}],\"data\":null}\n\n"+
+		"event: complete\n\n", string(fctx.Response.Body()))
+}
@@ -0,0 +1,121 @@
+package transport
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// UploadStore holds the files of a multipart request while its operation runs. MultipartForm streams every file part
+// into the store as it is read, and removes the stored files once the operation has ended.
+type UploadStore interface {
+	// Store saves the content of r, the file sent under key in the form.
+	Store(key string, r io.Reader) (StoredUpload, error)
+}
+
+// StoredUpload is a file saved by an UploadStore.
+type StoredUpload interface {
+	// Open returns a new reader over the content of the file. It is called once per operation path the file is
+	// mapped to, and the readers are closed when the operation ends. Readers implementing io.Seeker let resolvers
+	// rewind the file, the ones of DiskUploadStore and MemoryUploadStore do.
+	Open() (io.ReadCloser, error)
+
+	// Remove deletes the file from the store.
+	Remove() error
+}
+
+// DiskUploadStore stores uploaded files in temporary files.
+type DiskUploadStore struct {
+	// Dir is the directory the files are created in. Defaults to os.TempDir.
+	Dir string
+}
+
+var _ UploadStore = DiskUploadStore{}
+
+func (s DiskUploadStore) Store(key string, r io.Reader) (StoredUpload, error) {
+	f, err := ioutil.TempFile(s.Dir, "gqlgen-")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+
+	return diskUpload(f.Name()), nil
+}
+
+type diskUpload string
+
+func (u diskUpload) Open() (io.ReadCloser, error) {
+	return os.Open(string(u))
+}
+
+func (u diskUpload) Remove() error {
+	return os.Remove(string(u))
+}
+
+// MemoryUploadStore keeps uploaded files in memory. It counts the files it holds, which lets tests check that uploads
+// are cleaned up.
+type MemoryUploadStore struct {
+	mu    sync.Mutex
+	files int
+}
+
+var _ UploadStore = &MemoryUploadStore{}
+
+func (s *MemoryUploadStore) Store(key string, r io.Reader) (StoredUpload, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.files++
+	s.mu.Unlock()
+
+	return &memoryUpload{store: s, b: b}, nil
+}
+
+// Len returns the number of files the store holds.
+func (s *MemoryUploadStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.files
+}
+
+type memoryUpload struct {
+	store   *MemoryUploadStore
+	b       []byte
+	removed bool
+}
+
+func (u *memoryUpload) Open() (io.ReadCloser, error) {
+	return nopSeekCloser{&bytesReader{s: &u.b, i: 0, prevRune: -1}}, nil
+}
+
+func (u *memoryUpload) Remove() error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	if !u.removed {
+		u.removed = true
+		u.store.files--
+	}
+	return nil
+}
+
+// nopSeekCloser is ioutil.NopCloser keeping the Seek method of the reader it wraps.
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package transport_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql/handler/transport"
+)
+
+func TestUploadStore(t *testing.T) {
+	stores := map[string]transport.UploadStore{
+		"disk":   transport.DiskUploadStore{Dir: t.TempDir()},
+		"memory": &transport.MemoryUploadStore{},
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			stored, err := store.Store("0", strings.NewReader("test1"))
+			require.NoError(t, err)
+
+			// each path mapped to the file gets its own reader
+			for i := 0; i < 2; i++ {
+				r, err := stored.Open()
+				require.NoError(t, err)
+				b, err := ioutil.ReadAll(r)
+				require.NoError(t, err)
+				require.Equal(t, "test1", string(b))
+
+				seeker, ok := r.(io.ReadSeeker)
+				require.True(t, ok, "%T can not seek", r)
+				_, err = seeker.Seek(1, io.SeekStart)
+				require.NoError(t, err)
+				b, err = ioutil.ReadAll(seeker)
+				require.NoError(t, err)
+				require.Equal(t, "est1", string(b))
+
+				require.NoError(t, r.Close())
+			}
+
+			require.NoError(t, stored.Remove())
+		})
+	}
+
+	t.Run("disk files are removed", func(t *testing.T) {
+		dir := t.TempDir()
+		stored, err := transport.DiskUploadStore{Dir: dir}.Store("0", strings.NewReader("test1"))
+		require.NoError(t, err)
+
+		entries, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		require.NoError(t, stored.Remove())
+		_, err = os.Stat(dir + "/" + entries[0].Name())
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("memory store counts the files it holds", func(t *testing.T) {
+		store := &transport.MemoryUploadStore{}
+		stored, err := store.Store("0", strings.NewReader("test1"))
+		require.NoError(t, err)
+		require.Equal(t, 1, store.Len())
+
+		require.NoError(t, stored.Remove())
+		require.NoError(t, stored.Remove())
+		require.Equal(t, 0, store.Len())
+	})
+}
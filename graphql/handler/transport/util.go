@@ -17,6 +17,14 @@ func writeJson(w io.Writer, response *graphql.Response) {
 	w.Write(b)
 }
 
+func writeJsonBatch(w io.Writer, responses []*graphql.Response) {
+	b, err := json.Marshal(responses)
+	if err != nil {
+		panic(err)
+	}
+	w.Write(b)
+}
+
 func writeJsonError(w io.Writer, msg string) {
 	writeJson(w, &graphql.Response{Errors: gqlerror.List{{Message: msg}}})
 }
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -14,45 +16,104 @@ import (
 	"github.com/sujamess/fastgql/graphql"
 	"github.com/sujamess/fastgql/graphql/errcode"
 	"github.com/valyala/fasthttp"
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
-const (
-	connectionInitMsg      = "connection_init"      // Client -> Server
-	connectionTerminateMsg = "connection_terminate" // Client -> Server
-	startMsg               = "start"                // Client -> Server
-	stopMsg                = "stop"                 // Client -> Server
-	connectionAckMsg       = "connection_ack"       // Server -> Client
-	connectionErrorMsg     = "connection_error"     // Server -> Client
-	dataMsg                = "data"                 // Server -> Client
-	errorMsg               = "error"                // Server -> Client
-	completeMsg            = "complete"             // Server -> Client
-	connectionKeepAliveMsg = "ka"                   // Server -> Client
-)
-
 type (
 	Websocket struct {
-		Upgrader              websocket.FastHTTPUpgrader
+		Upgrader websocket.FastHTTPUpgrader
+		// InitFunc is called with the connection_init payload and a copy of the request the connection was upgraded
+		// from, as that one is recycled once the connection is hijacked. The request context it returns is the parent
+		// of every operation of the connection.
 		InitFunc              WebsocketInitFunc
+		InitTimeout           time.Duration
 		KeepAlivePingInterval time.Duration
+		// PingPongInterval sets how often the server pings the client, with websocket ping frames. A client that
+		// doesn't answer with a pong before the next ping is closed with CloseCodePongTimeout.
+		PingPongInterval time.Duration
+		// ReadIdleTimeout closes connections with CloseCodeReadIdleTimeout when nothing, pings and pongs included,
+		// has been received from the client for that long.
+		ReadIdleTimeout time.Duration
+		// CompressionLevel sets the flate level of outgoing messages when permessage-deflate was negotiated, which
+		// Upgrader.EnableCompression offers to clients. Defaults to the level of the websocket package.
+		CompressionLevel int
+
+		// InitContextFunc is called with the connection_init payload after InitFunc. The context it returns is the
+		// parent of every operation of the connection, and the payload it returns is sent in connection_ack.
+		InitContextFunc WebsocketInitContextFunc
+
+		// CloseFunc is called once the connection is closed, with the close code sent by either side.
+		// websocket.CloseAbnormalClosure means the connection was lost without one.
+		CloseFunc WebsocketCloseFunc
+
+		// OnOperationStart is called when an operation starts, its context holds the operation context.
+		OnOperationStart WebsocketOperationFunc
+
+		// OnOperationComplete is called when an operation started on the connection is over, whether it finished,
+		// was stopped by the client or the connection was closed.
+		OnOperationComplete WebsocketOperationFunc
+
+		// MaxActiveOperations caps the number of operations a connection can run at once, further ones are rejected
+		// with an error. 0 means no limit.
+		MaxActiveOperations int
+
+		// SendQueueSize sets how many data messages can wait to be sent to a client before OverflowPolicy applies.
+		// Defaults to 64.
+		SendQueueSize int
+
+		// OverflowPolicy decides what happens to the data messages of a client whose send queue is full. Defaults to
+		// OverflowCloseOperation.
+		OverflowPolicy OverflowPolicy
+
+		// WriteTimeout sets how long sending a message may take before the connection is closed, 0 means no limit.
+		WriteTimeout time.Duration
+
+		// OnQueueDepth is called with the number of messages waiting to be sent on a connection every time it
+		// changes, to feed metrics.
+		OnQueueDepth WebsocketQueueDepthFunc
 	}
 	wsConnection struct {
 		Websocket
-		ctx             *fasthttp.RequestCtx
+		// ctx is the context of the connection, the request context it was upgraded from is recycled once hijacked
+		ctx   context.Context
+		opCtx context.Context
+		// initRequestCtx is the copy of the request handed to InitFunc
+		initRequestCtx  *fasthttp.RequestCtx
+		request         *graphql.RequestInfo
 		conn            *websocket.Conn
-		active          map[string]context.CancelFunc
+		me              messageExchanger
+		queue           *sendQueue
+		active          map[string]*wsOperation
+		overflowed      map[string]struct{}
 		mu              sync.Mutex
 		keepAliveTicker *time.Ticker
 		exec            graphql.GraphExecutor
+		shutdown        *graphql.Shutdown
+		shuttingDown    bool
+		closeCode       int
+		awaitingPong    bool
 
 		initPayload InitPayload
 	}
-	operationMessage struct {
-		Payload json.RawMessage `json:"payload,omitempty"`
-		ID      string          `json:"id,omitempty"`
-		Type    string          `json:"type"`
+	// wsOperation is an operation running on a connection.
+	wsOperation struct {
+		cancel       context.CancelFunc
+		subscription bool
 	}
-	WebsocketInitFunc func(ctx *fasthttp.RequestCtx, initPayload InitPayload) (*fasthttp.RequestCtx, error)
+	WebsocketInitFunc      func(ctx *fasthttp.RequestCtx, initPayload InitPayload) (*fasthttp.RequestCtx, error)
+	WebsocketCloseFunc     func(ctx context.Context, closeCode int)
+	WebsocketOperationFunc func(ctx context.Context, id string)
+
+	// WebsocketInitContextFunc returns the context the operations of a connection run in, which must be derived from
+	// ctx, along with the payload of connection_ack, nil for none. An error rejects the connection.
+	WebsocketInitContextFunc func(ctx context.Context, initPayload InitPayload) (context.Context, *InitPayload, error)
+)
+
+// Close codes of the connections closed for being unresponsive, in the range RFC 6455 leaves to applications.
+const (
+	CloseCodeReadIdleTimeout = 4000
+	CloseCodePongTimeout     = 4001
 )
 
 var _ graphql.Transport = Websocket{}
@@ -62,15 +123,79 @@ func (t Websocket) Supports(ctx *fasthttp.RequestCtx) bool {
 }
 
 func (t Websocket) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
+	t.injectGraphQLWSSubprotocols()
+	// the request context is recycled once the connection is hijacked, the shutdown and the request are picked up
+	// beforehand
+	shutdown := graphql.GetShutdown(ctx)
+	request := graphql.CopyRequestInfo(ctx)
+	var initRequestCtx *fasthttp.RequestCtx
+	if t.InitFunc != nil {
+		initRequestCtx = &fasthttp.RequestCtx{}
+		initRequestCtx.Init(&ctx.Request, ctx.RemoteAddr(), nil)
+	}
 	err := t.Upgrader.Upgrade(ctx, func(ws *websocket.Conn) {
+		var me messageExchanger
+		switch ws.Subprotocol() {
+		case graphqltransportwsSubprotocol:
+			me = graphqltransportwsMessageExchanger{c: ws}
+		case graphqlwsSubprotocol, "":
+			// clients are required to send a subprotocol, to be backward compatible with the previous implementation
+			// we select "graphql-ws" by default
+			me = graphqlwsMessageExchanger{c: ws}
+		default:
+			msg := websocket.FormatCloseMessage(websocket.CloseProtocolError, fmt.Sprintf("unsupported negotiated subprotocol %s", ws.Subprotocol()))
+			_ = ws.WriteMessage(websocket.CloseMessage, msg)
+			_ = ws.Close()
+			return
+		}
+
+		if t.CompressionLevel != 0 {
+			if err := ws.SetCompressionLevel(t.CompressionLevel); err != nil {
+				log.Printf("unable to set websocket compression level: %s", err.Error())
+			}
+		}
+
+		// the server waits for the connection to be closed when shutting down
+		if shutdown != nil {
+			if !shutdown.Acquire() {
+				_ = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down"))
+				_ = ws.Close()
+				return
+			}
+			defer shutdown.Release()
+		}
+
+		connCtx := graphql.WithRequestInfo(context.Background(), request)
 		conn := wsConnection{
-			active:    map[string]context.CancelFunc{},
-			conn:      ws,
-			ctx:       ctx,
-			exec:      exec,
-			Websocket: t,
+			active:         map[string]*wsOperation{},
+			overflowed:     map[string]struct{}{},
+			queue:          newSendQueue(),
+			conn:           ws,
+			ctx:            connCtx,
+			opCtx:          connCtx,
+			initRequestCtx: initRequestCtx,
+			request:        request,
+			exec:           exec,
+			me:             me,
+			shutdown:       shutdown,
+			Websocket:      t,
+		}
+
+		// the close code sent by the client is recorded before the close frame is echoed
+		closeHandler := ws.CloseHandler()
+		ws.SetCloseHandler(func(code int, text string) error {
+			conn.setCloseCode(code)
+			return closeHandler(code, text)
+		})
+		if t.CloseFunc != nil {
+			defer func() {
+				t.CloseFunc(conn.opCtx, conn.getCloseCode())
+			}()
 		}
 
+		go conn.writeLoop()
+		defer conn.stopQueue()
+
 		if !conn.init() {
 			return
 		}
@@ -84,40 +209,150 @@ func (t Websocket) Do(ctx *fasthttp.RequestCtx, exec graphql.GraphExecutor) {
 	}
 }
 
+// injectGraphQLWSSubprotocols makes sure both supported subprotocols can be negotiated, while keeping any
+// subprotocols the user configured on the Upgrader. graphql-transport-ws is preferred when the client offers both.
+func (t *Websocket) injectGraphQLWSSubprotocols() {
+	if t.Upgrader.Subprotocols == nil {
+		t.Upgrader.Subprotocols = []string{graphqltransportwsSubprotocol, graphqlwsSubprotocol}
+		return
+	}
+
+	subprotocols := make([]string, 0, len(t.Upgrader.Subprotocols)+2)
+	subprotocols = append(subprotocols, t.Upgrader.Subprotocols...)
+	for _, subprotocol := range []string{graphqltransportwsSubprotocol, graphqlwsSubprotocol} {
+		found := false
+		for _, existing := range t.Upgrader.Subprotocols {
+			if existing == subprotocol {
+				found = true
+				break
+			}
+		}
+		if !found {
+			subprotocols = append(subprotocols, subprotocol)
+		}
+	}
+	t.Upgrader.Subprotocols = subprotocols
+}
+
+// isGraphQLTransportWS reports whether the connection negotiated the graphql-transport-ws protocol.
+func (c *wsConnection) isGraphQLTransportWS() bool {
+	_, ok := c.me.(graphqltransportwsMessageExchanger)
+	return ok
+}
+
 func (c *wsConnection) init() bool {
-	message := c.readOp()
-	if message == nil {
-		c.close(websocket.CloseProtocolError, "decoding error")
+	if c.InitTimeout != 0 {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.InitTimeout))
+	}
+
+	var m message
+	var err error
+	for {
+		m, err = c.me.NextMessage()
+		if err != nil {
+			break
+		}
+		// ping and pong may be exchanged before the connection is initialised
+		if m.t == pingMessageType {
+			c.write(&message{t: pongMessageType, payload: m.payload})
+			continue
+		}
+		if m.t != pongMessageType {
+			break
+		}
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, errReadTimeout):
+			if c.isGraphQLTransportWS() {
+				c.close(closeCodeConnectionInitTimeout, "connection initialisation timeout")
+			} else {
+				c.close(websocket.CloseProtocolError, "connection initialisation timeout")
+			}
+		case errors.Is(err, errInvalidMsg):
+			if c.isGraphQLTransportWS() {
+				c.close(closeCodeBadRequest, "invalid message received")
+			} else {
+				c.sendConnectionError("invalid json")
+				c.close(websocket.CloseProtocolError, "decoding error")
+			}
+		case errors.Is(err, errWsConnClosed):
+		default:
+			if !c.isGraphQLTransportWS() {
+				c.sendConnectionError("invalid json: %T %s", err, err.Error())
+			}
+			c.close(websocket.CloseProtocolError, "decoding error")
+		}
 		return false
 	}
 
-	switch message.Type {
-	case connectionInitMsg:
-		if len(message.Payload) > 0 {
+	if c.InitTimeout != 0 {
+		_ = c.conn.SetReadDeadline(time.Time{})
+	}
+
+	switch m.t {
+	case initMessageType:
+		if len(m.payload) > 0 {
 			c.initPayload = make(InitPayload)
-			err := json.Unmarshal(message.Payload, &c.initPayload)
+			err := json.Unmarshal(m.payload, &c.initPayload)
 			if err != nil {
+				if c.isGraphQLTransportWS() {
+					c.close(closeCodeBadRequest, "invalid init payload")
+				}
 				return false
 			}
 		}
 
 		if c.InitFunc != nil {
-			ctx, err := c.InitFunc(c.ctx, c.initPayload)
+			ctx, err := c.InitFunc(c.initRequestCtx, c.initPayload)
 			if err != nil {
-				c.sendConnectionError(err.Error())
-				c.close(websocket.CloseNormalClosure, "terminated")
+				c.rejectInit(err)
 				return false
 			}
-			c.ctx = ctx
+			// the request context returned by InitFunc holds the values of the operations, it is owned by the
+			// connection rather than recycled like the one it was upgraded from
+			c.opCtx = graphql.WithRequestInfo(ctx, c.request)
+		}
+
+		var ackPayload json.RawMessage
+		if c.InitContextFunc != nil {
+			ctx, payload, err := c.InitContextFunc(c.opCtx, c.initPayload)
+			if err != nil {
+				c.rejectInit(err)
+				return false
+			}
+			c.opCtx = ctx
+
+			if payload != nil {
+				ackPayload, err = json.Marshal(payload)
+				if err != nil {
+					panic(err)
+				}
+			}
 		}
 
-		c.write(&operationMessage{Type: connectionAckMsg})
-		c.write(&operationMessage{Type: connectionKeepAliveMsg})
-	case connectionTerminateMsg:
+		c.write(&message{t: connectionAckMessageType, payload: ackPayload})
+		if !c.isGraphQLTransportWS() {
+			c.write(&message{t: keepAliveMessageType})
+		}
+	case connectionCloseMessageType:
 		c.close(websocket.CloseNormalClosure, "terminated")
 		return false
+	case startMessageType:
+		if c.isGraphQLTransportWS() {
+			c.close(closeCodeUnauthorized, "unauthorized")
+			return false
+		}
+		c.sendConnectionError("unexpected message %s", m.t)
+		c.close(websocket.CloseProtocolError, "unexpected message")
+		return false
 	default:
-		c.sendConnectionError("unexpected message %s", message.Type)
+		if c.isGraphQLTransportWS() {
+			c.close(closeCodeBadRequest, fmt.Sprintf("unexpected message %s", m.t))
+			return false
+		}
+		c.sendConnectionError("unexpected message %s", m.t)
 		c.close(websocket.CloseProtocolError, "unexpected message")
 		return false
 	}
@@ -125,10 +360,14 @@ func (c *wsConnection) init() bool {
 	return true
 }
 
-func (c *wsConnection) write(msg *operationMessage) {
-	c.mu.Lock()
-	c.conn.WriteJSON(msg)
-	c.mu.Unlock()
+// rejectInit refuses the connection after the init hook returned err.
+func (c *wsConnection) rejectInit(err error) {
+	if c.isGraphQLTransportWS() {
+		c.close(closeCodeForbidden, err.Error())
+		return
+	}
+	c.sendConnectionError(err.Error())
+	c.close(websocket.CloseNormalClosure, "terminated")
 }
 
 func (c *wsConnection) run() {
@@ -138,6 +377,13 @@ func (c *wsConnection) run() {
 	defer func() {
 		cancel()
 		c.close(websocket.CloseAbnormalClosure, "unexpected closure")
+
+		// the operations can't reach the client anymore
+		c.mu.Lock()
+		for _, op := range c.active {
+			op.cancel()
+		}
+		c.mu.Unlock()
 	}()
 
 	// Create a timer that will fire every interval to keep the connection alive.
@@ -149,28 +395,76 @@ func (c *wsConnection) run() {
 		go c.keepAlive(ctx)
 	}
 
+	if c.shutdown != nil {
+		go c.watchShutdown(ctx)
+	}
+
+	if c.ReadIdleTimeout != 0 || c.PingPongInterval != 0 {
+		c.watchLiveness(ctx)
+	}
+
 	for {
+		c.extendReadDeadline()
 		start := graphql.Now()
-		message := c.readOp()
-		if message == nil {
+		m, err := c.me.NextMessage()
+		if err != nil {
+			switch {
+			case errors.Is(err, errWsConnClosed), errors.Is(err, net.ErrClosed):
+			case errors.Is(err, errReadTimeout):
+				// the deadline also ends the read loop of connections closed for other reasons, their close code
+				// was recorded first
+				if c.ReadIdleTimeout != 0 {
+					c.closeUnresponsive(CloseCodeReadIdleTimeout, "read idle timeout")
+				}
+			case errors.Is(err, errInvalidMsg):
+				if c.isGraphQLTransportWS() {
+					c.close(closeCodeBadRequest, "invalid message received")
+				} else {
+					c.sendConnectionError("invalid json")
+				}
+			default:
+				if !c.isGraphQLTransportWS() {
+					c.sendConnectionError("invalid json: %T %s", err, err.Error())
+				}
+			}
 			return
 		}
 
-		switch message.Type {
-		case startMsg:
-			c.subscribe(start, message)
-		case stopMsg:
+		switch m.t {
+		case startMessageType:
+			if !c.subscribe(start, &m) {
+				return
+			}
+		case stopMessageType:
 			c.mu.Lock()
-			closer := c.active[message.ID]
+			op := c.active[m.id]
+			delete(c.active, m.id)
 			c.mu.Unlock()
-			if closer != nil {
-				closer()
+			if op != nil {
+				op.cancel()
 			}
-		case connectionTerminateMsg:
+		case connectionCloseMessageType:
 			c.close(websocket.CloseNormalClosure, "terminated")
 			return
+		case pingMessageType:
+			c.write(&message{t: pongMessageType, payload: m.payload})
+		case pongMessageType:
+			// pongs are only acknowledgements of our own pings
+			c.receivedPong()
+		case initMessageType:
+			if c.isGraphQLTransportWS() {
+				c.close(closeCodeTooManyInitialisationRequests, "too many initialisation requests")
+				return
+			}
+			c.sendConnectionError("unexpected message %s", m.t)
+			c.close(websocket.CloseProtocolError, "unexpected message")
+			return
 		default:
-			c.sendConnectionError("unexpected message %s", message.Type)
+			if c.isGraphQLTransportWS() {
+				c.close(closeCodeBadRequest, fmt.Sprintf("unexpected message %s", m.t))
+				return
+			}
+			c.sendConnectionError("unexpected message %s", m.t)
 			c.close(websocket.CloseProtocolError, "unexpected message")
 			return
 		}
@@ -178,79 +472,263 @@ func (c *wsConnection) run() {
 }
 
 func (c *wsConnection) keepAlive(ctx context.Context) {
+	// graphql-transport-ws has no keep-alive message, pings serve the same purpose there
+	t := keepAliveMessageType
+	if c.isGraphQLTransportWS() {
+		t = pingMessageType
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			c.keepAliveTicker.Stop()
 			return
 		case <-c.keepAliveTicker.C:
-			c.write(&operationMessage{Type: connectionKeepAliveMsg})
+			c.write(&message{t: t})
 		}
 	}
 }
 
-func (c *wsConnection) subscribe(start time.Time, message *operationMessage) {
-	graphql.StartOperationTrace(c.ctx)
-	var params *graphql.RawParams
-	if err := jsonDecode(bytes.NewReader(message.Payload), &params); err != nil {
-		c.sendError(message.ID, &gqlerror.Error{Message: "invalid json"})
-		c.complete(message.ID)
+// watchLiveness tracks the frames received from the client, and pings it every PingPongInterval until ctx is done.
+func (c *wsConnection) watchLiveness(ctx context.Context) {
+	// control frames are handled while reading the next message, they don't reach the read loop
+	pingHandler := c.conn.PingHandler()
+	c.conn.SetPingHandler(func(appData string) error {
+		c.extendReadDeadline()
+		return pingHandler(appData)
+	})
+	c.conn.SetPongHandler(func(string) error {
+		c.extendReadDeadline()
+		c.receivedPong()
+		return nil
+	})
+
+	if c.PingPongInterval != 0 {
+		go c.pingPong(ctx)
+	}
+}
+
+func (c *wsConnection) pingPong(ctx context.Context) {
+	ticker := time.NewTicker(c.PingPongInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		missed := c.awaitingPong
+		c.awaitingPong = true
+		c.mu.Unlock()
+
+		if missed {
+			c.closeUnresponsive(CloseCodePongTimeout, "pong timeout")
+			return
+		}
+		_ = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.PingPongInterval))
+	}
+}
+
+func (c *wsConnection) receivedPong() {
+	c.mu.Lock()
+	c.awaitingPong = false
+	c.mu.Unlock()
+}
+
+// extendReadDeadline gives the client ReadIdleTimeout more to send something.
+func (c *wsConnection) extendReadDeadline() {
+	if c.ReadIdleTimeout == 0 {
 		return
 	}
 
+	// the deadline set to end the read loop of a closed connection must not be pushed back
+	c.queue.mu.Lock()
+	if !c.queue.stopped {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.ReadIdleTimeout))
+	}
+	c.queue.mu.Unlock()
+}
+
+// closeUnresponsive closes the connection of a client that may be gone, without waiting for the queued messages.
+// Writes are given up on after a while, a client that doesn't read would hold the connection open.
+func (c *wsConnection) closeUnresponsive(closeCode int, message string) {
+	timeout := c.PingPongInterval
+	if timeout == 0 {
+		timeout = c.ReadIdleTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	c.queue.mu.Lock()
+	if c.queue.deadline.IsZero() {
+		c.queue.deadline = deadline
+	}
+	c.queue.mu.Unlock()
+
+	c.abort(closeCode, message)
+	// abort leaves the connections that are already being closed alone, their writer may be blocked all the same
+	_ = c.conn.UnderlyingConn().SetWriteDeadline(deadline)
+}
+
+// watchShutdown completes the subscriptions of the connection when the server starts shutting down. The connection is
+// closed once the queries and mutations in flight are over.
+func (c *wsConnection) watchShutdown(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-c.shutdown.Done():
+	}
+
+	c.mu.Lock()
+	c.shuttingDown = true
+	for _, op := range c.active {
+		// the operation stays active, so it gets completed like any subscription that ended on the server side
+		if op.subscription {
+			op.cancel()
+		}
+	}
+	idle := len(c.active) == 0
+	c.mu.Unlock()
+
+	if idle {
+		c.close(websocket.CloseGoingAway, "server is shutting down")
+	}
+}
+
+// subscribe starts the operation carried by the start message. It returns false when the client violated the
+// protocol and the connection has been closed.
+func (c *wsConnection) subscribe(start time.Time, msg *message) bool {
+	// start messages are handled one at a time, the operations can only end in the meantime
+	c.mu.Lock()
+	_, exists := c.active[msg.id]
+	tooMany := c.MaxActiveOperations > 0 && len(c.active) >= c.MaxActiveOperations
+	c.mu.Unlock()
+
+	if exists {
+		if c.isGraphQLTransportWS() {
+			c.close(closeCodeSubscriberAlreadyExists, fmt.Sprintf("subscriber for %s already exists", msg.id))
+			return false
+		}
+		// the running operation is left alone, a complete would end it on the client
+		c.sendError(msg.id, &gqlerror.Error{Message: fmt.Sprintf("subscriber for %s already exists", msg.id)})
+		return true
+	}
+
+	if tooMany {
+		c.sendError(msg.id, &gqlerror.Error{Message: fmt.Sprintf("too many active operations, at most %d are allowed", c.MaxActiveOperations)})
+		if !c.isGraphQLTransportWS() {
+			c.complete(msg.id)
+		}
+		return true
+	}
+
+	opCtx := graphql.WithOperationTrace(c.opCtx)
+	if c.initPayload != nil {
+		opCtx = withInitPayload(opCtx, c.initPayload)
+	}
+
+	var params *graphql.RawParams
+	if err := jsonDecode(bytes.NewReader(msg.payload), &params); err != nil {
+		c.sendError(msg.id, &gqlerror.Error{Message: "invalid json"})
+		c.complete(msg.id)
+		return true
+	}
+
 	params.ReadTime = graphql.TraceTiming{
 		Start: start,
 		End:   graphql.Now(),
 	}
 
-	rc, err := c.exec.CreateOperationContext(c.ctx, params)
+	rc, err := c.exec.CreateOperationContext(opCtx, params)
 	if err != nil {
-		resp := c.exec.DispatchError(graphql.WithOperationContext(c.ctx, rc), err)
-		switch errcode.GetErrorKind(err) {
-		case errcode.KindProtocol:
-			c.sendError(message.ID, resp.Errors...)
+		resp := c.exec.DispatchError(graphql.WithOperationContext(opCtx, rc), err)
+		switch {
+		case c.isGraphQLTransportWS():
+			// error messages terminate the operation, no complete follows them
+			c.sendError(msg.id, resp.Errors...)
+			return true
+		case errcode.GetErrorKind(err) != errcode.KindUser:
+			c.sendError(msg.id, resp.Errors...)
 		default:
-			c.sendResponse(message.ID, &graphql.Response{Errors: err})
+			c.sendResponse(msg.id, &graphql.Response{Errors: err})
 		}
 
-		c.complete(message.ID)
-		return
+		c.complete(msg.id)
+		return true
 	}
 
-	graphql.WithOperationContext(c.ctx, rc)
-
-	if c.initPayload != nil {
-		withInitPayload(c.ctx, c.initPayload)
+	ctx, cancel := context.WithCancel(graphql.WithStreaming(opCtx))
+	if c.shutdown != nil {
+		ctx = graphql.WithShutdown(ctx, c.shutdown)
 	}
 
-	ctx, cancel := context.WithCancel(c.ctx)
 	c.mu.Lock()
-	c.active[message.ID] = cancel
+	if c.shuttingDown {
+		c.mu.Unlock()
+		cancel()
+		c.sendError(msg.id, &gqlerror.Error{Message: "server is shutting down"})
+		if !c.isGraphQLTransportWS() {
+			c.complete(msg.id)
+		}
+		return true
+	}
+	c.active[msg.id] = &wsOperation{cancel: cancel, subscription: rc.Operation.Operation == ast.Subscription}
 	c.mu.Unlock()
 
+	if c.OnOperationStart != nil {
+		c.OnOperationStart(graphql.WithOperationContext(ctx, rc), msg.id)
+	}
+
 	go func() {
+		if c.OnOperationComplete != nil {
+			defer c.OnOperationComplete(graphql.WithOperationContext(ctx, rc), msg.id)
+		}
 		defer func() {
 			if r := recover(); r != nil {
 				userErr := rc.Recover(ctx, r)
-				c.sendError(message.ID, &gqlerror.Error{Message: userErr.Error()})
+				c.sendError(msg.id, &gqlerror.Error{Message: userErr.Error()})
 			}
 		}()
 		responses, ctx := c.exec.DispatchOperation(ctx, rc)
+		var fatal bool
 		for {
 			response := responses(ctx)
 			if response == nil {
 				break
 			}
 
-			c.sendResponse(message.ID, response)
+			// a subscription ended by an error gets an error message instead of a result
+			if response.Fatal {
+				c.sendError(msg.id, response.Errors...)
+				fatal = true
+				break
+			}
+
+			c.sendResponse(msg.id, response)
 		}
-		c.complete(message.ID)
 
 		c.mu.Lock()
-		delete(c.active, message.ID)
+		_, active := c.active[msg.id]
+		delete(c.active, msg.id)
+		delete(c.overflowed, msg.id)
+		drained := c.shuttingDown && len(c.active) == 0
 		c.mu.Unlock()
+
+		// graphql-transport-ws clients do not expect a complete for operations they stopped themselves, nor after an
+		// error message
+		if !c.isGraphQLTransportWS() || active && !fatal {
+			c.complete(msg.id)
+		}
 		cancel()
+
+		if drained {
+			c.close(websocket.CloseGoingAway, "server is shutting down")
+		}
 	}()
+
+	return true
 }
 
 func (c *wsConnection) sendResponse(id string, response *graphql.Response) {
@@ -258,15 +736,15 @@ func (c *wsConnection) sendResponse(id string, response *graphql.Response) {
 	if err != nil {
 		panic(err)
 	}
-	c.write(&operationMessage{
-		Payload: b,
-		ID:      id,
-		Type:    dataMsg,
+	c.write(&message{
+		payload: b,
+		id:      id,
+		t:       dataMessageType,
 	})
 }
 
 func (c *wsConnection) complete(id string) {
-	c.write(&operationMessage{ID: id, Type: completeMsg})
+	c.write(&message{id: id, t: completeMessageType})
 }
 
 func (c *wsConnection) sendError(id string, errors ...*gqlerror.Error) {
@@ -278,7 +756,7 @@ func (c *wsConnection) sendError(id string, errors ...*gqlerror.Error) {
 	if err != nil {
 		panic(err)
 	}
-	c.write(&operationMessage{Type: errorMsg, ID: id, Payload: b})
+	c.write(&message{t: errorMessageType, id: id, payload: b})
 }
 
 func (c *wsConnection) sendConnectionError(format string, args ...interface{}) {
@@ -287,29 +765,23 @@ func (c *wsConnection) sendConnectionError(format string, args ...interface{}) {
 		panic(err)
 	}
 
-	c.write(&operationMessage{Type: connectionErrorMsg, Payload: b})
+	c.write(&message{t: connectionErrorMessageType, payload: b})
 }
 
-func (c *wsConnection) readOp() *operationMessage {
-	_, r, err := c.conn.NextReader()
-	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
-		return nil
-	} else if err != nil {
-		c.sendConnectionError("invalid json: %T %s", err, err.Error())
-		return nil
-	}
-	message := operationMessage{}
-	if err := jsonDecode(r, &message); err != nil {
-		c.sendConnectionError("invalid json")
-		return nil
+func (c *wsConnection) setCloseCode(closeCode int) {
+	c.mu.Lock()
+	if c.closeCode == 0 {
+		c.closeCode = closeCode
 	}
-
-	return &message
+	c.mu.Unlock()
 }
 
-func (c *wsConnection) close(closeCode int, message string) {
+func (c *wsConnection) getCloseCode() int {
 	c.mu.Lock()
-	_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, message))
-	c.mu.Unlock()
-	_ = c.conn.Close()
+	defer c.mu.Unlock()
+
+	if c.closeCode == 0 {
+		return websocket.CloseAbnormalClosure
+	}
+	return c.closeCode
 }
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fasthttp/websocket"
+)
+
+// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const (
+	graphqltransportwsSubprotocol = "graphql-transport-ws"
+
+	graphqltransportwsConnectionInitMsg = graphqltransportwsMessageType("connection_init")
+	graphqltransportwsConnectionAckMsg  = graphqltransportwsMessageType("connection_ack")
+	graphqltransportwsSubscribeMsg      = graphqltransportwsMessageType("subscribe")
+	graphqltransportwsNextMsg           = graphqltransportwsMessageType("next")
+	graphqltransportwsErrorMsg          = graphqltransportwsMessageType("error")
+	graphqltransportwsCompleteMsg       = graphqltransportwsMessageType("complete")
+	graphqltransportwsPingMsg           = graphqltransportwsMessageType("ping")
+	graphqltransportwsPongMsg           = graphqltransportwsMessageType("pong")
+)
+
+// Close codes defined by the graphql-transport-ws protocol
+const (
+	closeCodeBadRequest                    = 4400
+	closeCodeUnauthorized                  = 4401
+	closeCodeForbidden                     = 4403
+	closeCodeConnectionInitTimeout         = 4408
+	closeCodeSubscriberAlreadyExists       = 4409
+	closeCodeTooManyInitialisationRequests = 4429
+)
+
+type (
+	graphqltransportwsMessageExchanger struct {
+		c *websocket.Conn
+	}
+
+	graphqltransportwsMessage struct {
+		Payload json.RawMessage               `json:"payload,omitempty"`
+		ID      string                        `json:"id,omitempty"`
+		Type    graphqltransportwsMessageType `json:"type"`
+	}
+
+	graphqltransportwsMessageType string
+)
+
+func (me graphqltransportwsMessageExchanger) NextMessage() (message, error) {
+	_, r, err := me.c.NextReader()
+	if err != nil {
+		return message{}, handleNextReaderError(err)
+	}
+
+	var graphqltransportwsMessage graphqltransportwsMessage
+	if err := jsonDecode(r, &graphqltransportwsMessage); err != nil {
+		return message{}, errInvalidMsg
+	}
+
+	return graphqltransportwsMessage.toMessage()
+}
+
+func (me graphqltransportwsMessageExchanger) Send(m *message) error {
+	msg := &graphqltransportwsMessage{}
+	if err := msg.fromMessage(m); err != nil {
+		return err
+	}
+
+	return me.c.WriteJSON(msg)
+}
+
+func (m graphqltransportwsMessage) toMessage() (message, error) {
+	var t messageType
+	var err error
+	switch m.Type {
+	default:
+		err = fmt.Errorf("%w: unexpected message type %s", errInvalidMsg, m.Type)
+	case graphqltransportwsConnectionInitMsg:
+		t = initMessageType
+	case graphqltransportwsSubscribeMsg:
+		t = startMessageType
+	case graphqltransportwsCompleteMsg:
+		t = stopMessageType
+	case graphqltransportwsPingMsg:
+		t = pingMessageType
+	case graphqltransportwsPongMsg:
+		t = pongMessageType
+	}
+
+	return message{
+		payload: m.Payload,
+		id:      m.ID,
+		t:       t,
+	}, err
+}
+
+func (m *graphqltransportwsMessage) fromMessage(msg *message) (err error) {
+	m.ID = msg.id
+	m.Payload = msg.payload
+
+	switch msg.t {
+	default:
+		err = fmt.Errorf("invalid server->client message type %s", msg.t)
+	case connectionAckMessageType:
+		m.Type = graphqltransportwsConnectionAckMsg
+	case dataMessageType:
+		m.Type = graphqltransportwsNextMsg
+	case completeMessageType:
+		m.Type = graphqltransportwsCompleteMsg
+	case errorMessageType:
+		m.Type = graphqltransportwsErrorMsg
+	case pingMessageType:
+		m.Type = graphqltransportwsPingMsg
+	case pongMessageType:
+		m.Type = graphqltransportwsPongMsg
+	}
+
+	return err
+}
@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fasthttp/websocket"
+)
+
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+const (
+	graphqlwsSubprotocol = "graphql-ws"
+
+	graphqlwsConnectionInitMsg      = graphqlwsMessageType("connection_init")
+	graphqlwsConnectionTerminateMsg = graphqlwsMessageType("connection_terminate")
+	graphqlwsStartMsg               = graphqlwsMessageType("start")
+	graphqlwsStopMsg                = graphqlwsMessageType("stop")
+	graphqlwsConnectionAckMsg       = graphqlwsMessageType("connection_ack")
+	graphqlwsConnectionErrorMsg     = graphqlwsMessageType("connection_error")
+	graphqlwsDataMsg                = graphqlwsMessageType("data")
+	graphqlwsErrorMsg               = graphqlwsMessageType("error")
+	graphqlwsCompleteMsg            = graphqlwsMessageType("complete")
+	graphqlwsConnectionKeepAliveMsg = graphqlwsMessageType("ka")
+)
+
+type (
+	graphqlwsMessageExchanger struct {
+		c *websocket.Conn
+	}
+
+	graphqlwsMessage struct {
+		Payload json.RawMessage      `json:"payload,omitempty"`
+		ID      string               `json:"id,omitempty"`
+		Type    graphqlwsMessageType `json:"type"`
+	}
+
+	graphqlwsMessageType string
+)
+
+func (me graphqlwsMessageExchanger) NextMessage() (message, error) {
+	_, r, err := me.c.NextReader()
+	if err != nil {
+		return message{}, handleNextReaderError(err)
+	}
+
+	var graphqlwsMessage graphqlwsMessage
+	if err := jsonDecode(r, &graphqlwsMessage); err != nil {
+		return message{}, errInvalidMsg
+	}
+
+	return graphqlwsMessage.toMessage()
+}
+
+func (me graphqlwsMessageExchanger) Send(m *message) error {
+	msg := &graphqlwsMessage{}
+	if err := msg.fromMessage(m); err != nil {
+		return err
+	}
+
+	return me.c.WriteJSON(msg)
+}
+
+func (m graphqlwsMessage) toMessage() (message, error) {
+	var t messageType
+	var err error
+	switch m.Type {
+	default:
+		err = fmt.Errorf("%w: unexpected message type %s", errInvalidMsg, m.Type)
+	case graphqlwsConnectionInitMsg:
+		t = initMessageType
+	case graphqlwsConnectionTerminateMsg:
+		t = connectionCloseMessageType
+	case graphqlwsStartMsg:
+		t = startMessageType
+	case graphqlwsStopMsg:
+		t = stopMessageType
+	}
+
+	return message{
+		payload: m.Payload,
+		id:      m.ID,
+		t:       t,
+	}, err
+}
+
+func (m *graphqlwsMessage) fromMessage(msg *message) (err error) {
+	m.ID = msg.id
+	m.Payload = msg.payload
+
+	switch msg.t {
+	default:
+		err = fmt.Errorf("invalid server->client message type %s", msg.t)
+	case connectionAckMessageType:
+		m.Type = graphqlwsConnectionAckMsg
+	case keepAliveMessageType:
+		m.Type = graphqlwsConnectionKeepAliveMsg
+	case connectionErrorMessageType:
+		m.Type = graphqlwsConnectionErrorMsg
+	case dataMessageType:
+		m.Type = graphqlwsDataMsg
+	case completeMessageType:
+		m.Type = graphqlwsCompleteMsg
+	case errorMessageType:
+		m.Type = graphqlwsErrorMsg
+	}
+
+	return err
+}
@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+
+	"github.com/fasthttp/websocket"
+)
+
+type (
+	messageType int
+
+	// message is the protocol independent form of a websocket message. Each supported subprotocol converts its
+	// own wire format from and into it.
+	message struct {
+		payload json.RawMessage
+		id      string
+		t       messageType
+	}
+
+	messageExchanger interface {
+		NextMessage() (message, error)
+		Send(m *message) error
+	}
+)
+
+const (
+	initMessageType messageType = iota
+	connectionAckMessageType
+	keepAliveMessageType
+	connectionErrorMessageType
+	connectionCloseMessageType
+	startMessageType
+	stopMessageType
+	dataMessageType
+	completeMessageType
+	errorMessageType
+	pingMessageType
+	pongMessageType
+)
+
+var (
+	errReadTimeout  = errors.New("read timeout")
+	errWsConnClosed = errors.New("websocket connection closed")
+	errInvalidMsg   = errors.New("invalid message received")
+)
+
+func (t messageType) String() string {
+	var text string
+	switch t {
+	default:
+		text = "unknown"
+	case initMessageType:
+		text = "init"
+	case connectionAckMessageType:
+		text = "connection ack"
+	case keepAliveMessageType:
+		text = "keep alive"
+	case connectionErrorMessageType:
+		text = "connection error"
+	case connectionCloseMessageType:
+		text = "connection close"
+	case startMessageType:
+		text = "start"
+	case stopMessageType:
+		text = "stop"
+	case dataMessageType:
+		text = "data"
+	case completeMessageType:
+		text = "complete"
+	case errorMessageType:
+		text = "error"
+	case pingMessageType:
+		text = "ping"
+	case pongMessageType:
+		text = "pong"
+	}
+	return text
+}
+
+func handleNextReaderError(err error) error {
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseNoStatusReceived) {
+		return errWsConnClosed
+	}
+
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return errReadTimeout
+	}
+
+	return err
+}
@@ -0,0 +1,280 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// OverflowPolicy decides what happens to a data message sent to a client whose send queue is full. Other messages
+// are queued past the size of the queue, the protocol breaks without them, up to twice that size, beyond which the
+// connection is closed with websocket.CloseTryAgainLater.
+type OverflowPolicy int
+
+const (
+	// OverflowCloseOperation stops the operation the message belongs to, and sends the client an error for it.
+	OverflowCloseOperation OverflowPolicy = iota
+
+	// OverflowDropOldest drops the oldest data message of the queue to make room for the new one, which is dropped
+	// instead when the queue only holds other messages.
+	OverflowDropOldest
+
+	// OverflowCloseConnection closes the connection with websocket.CloseTryAgainLater, dropping the messages still
+	// in the queue.
+	OverflowCloseConnection
+)
+
+// closeWriteTimeout bounds the writes of an aborted connection when WriteTimeout doesn't, a client that doesn't read
+// would otherwise keep the writer blocked. A client that is just slow still gets the close frame within it.
+var closeWriteTimeout = 10 * time.Second
+
+// WebsocketQueueDepthFunc is called with the number of messages waiting in the send queue of a connection, every
+// time it changes.
+type WebsocketQueueDepthFunc func(ctx context.Context, depth int)
+
+type (
+	// sendQueue holds the messages of a connection until its writer sends them, so a slow client only holds up
+	// its own connection.
+	sendQueue struct {
+		mu       sync.Mutex
+		messages []*outboundMessage
+		stopped  bool
+		// deadline is set when aborting the connection, the writes are given up on after it
+		deadline time.Time
+		wake     chan struct{}
+		done     chan struct{}
+	}
+
+	// outboundMessage is a message waiting in the send queue, or the close frame ending it when close is set.
+	outboundMessage struct {
+		msg       *message
+		close     bool
+		closeCode int
+		closeText string
+	}
+)
+
+func newSendQueue() *sendQueue {
+	return &sendQueue{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+}
+
+func (t Websocket) sendQueueSize() int {
+	if t.SendQueueSize == 0 {
+		return 64
+	}
+	return t.SendQueueSize
+}
+
+// write queues msg, applying the overflow policy when the queue is full.
+func (c *wsConnection) write(msg *message) {
+	q := c.queue
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+
+	// a client that doesn't even read the messages the protocol needs, like pongs, isn't kept up with
+	if len(q.messages) >= 2*c.sendQueueSize() {
+		q.mu.Unlock()
+		c.abort(websocket.CloseTryAgainLater, "send queue is full")
+		return
+	}
+
+	if msg.t == dataMessageType {
+		c.mu.Lock()
+		_, overflowed := c.overflowed[msg.id]
+		c.mu.Unlock()
+		// the operation has been stopped for overflowing, it may still be producing results
+		if overflowed {
+			q.mu.Unlock()
+			return
+		}
+
+		if len(q.messages) >= c.sendQueueSize() {
+			switch c.OverflowPolicy {
+			case OverflowDropOldest:
+				dropped := false
+				for i, queued := range q.messages {
+					if queued.msg != nil && queued.msg.t == dataMessageType {
+						q.messages = append(q.messages[:i], q.messages[i+1:]...)
+						dropped = true
+						break
+					}
+				}
+				// the queue only holds messages the protocol needs, the new data message is the oldest one
+				if !dropped {
+					q.mu.Unlock()
+					return
+				}
+			case OverflowCloseConnection:
+				q.mu.Unlock()
+				c.abort(websocket.CloseTryAgainLater, "send queue is full")
+				return
+			default:
+				q.mu.Unlock()
+				c.closeOverflowedOperation(msg.id)
+				return
+			}
+		}
+	}
+
+	q.messages = append(q.messages, &outboundMessage{msg: msg})
+	depth := len(q.messages)
+	q.mu.Unlock()
+
+	c.notifyQueue(depth)
+}
+
+// closeOverflowedOperation stops an operation whose results don't fit in the send queue.
+func (c *wsConnection) closeOverflowedOperation(id string) {
+	c.mu.Lock()
+	op := c.active[id]
+	if op != nil {
+		c.overflowed[id] = struct{}{}
+		// error messages end graphql-transport-ws operations, graphql-ws ones still get their complete when they end
+		if c.isGraphQLTransportWS() {
+			delete(c.active, id)
+		}
+	}
+	c.mu.Unlock()
+	if op == nil {
+		return
+	}
+
+	op.cancel()
+	c.sendError(id, &gqlerror.Error{Message: "the client doesn't read the results fast enough, the operation has been stopped"})
+}
+
+func (c *wsConnection) notifyQueue(depth int) {
+	select {
+	case c.queue.wake <- struct{}{}:
+	default:
+	}
+
+	if c.OnQueueDepth != nil {
+		c.OnQueueDepth(c.ctx, depth)
+	}
+}
+
+// writeLoop sends the queued messages until the connection is closed.
+func (c *wsConnection) writeLoop() {
+	q := c.queue
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		if len(q.messages) == 0 {
+			stopped := q.stopped
+			q.mu.Unlock()
+			if stopped {
+				return
+			}
+			<-q.wake
+			continue
+		}
+		m := q.messages[0]
+		q.messages = q.messages[1:]
+		depth := len(q.messages)
+		deadline := q.deadline
+		q.mu.Unlock()
+
+		if c.OnQueueDepth != nil {
+			c.OnQueueDepth(c.ctx, depth)
+		}
+
+		if c.WriteTimeout != 0 {
+			if d := time.Now().Add(c.WriteTimeout); deadline.IsZero() || d.Before(deadline) {
+				deadline = d
+			}
+		}
+		if !deadline.IsZero() {
+			_ = c.conn.SetWriteDeadline(deadline)
+		}
+
+		if m.close {
+			_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(m.closeCode, m.closeText))
+			c.stopQueue()
+			_ = c.conn.Close()
+			return
+		}
+
+		if err := c.me.Send(m.msg); err != nil {
+			c.stopQueue()
+			_ = c.conn.Close()
+			return
+		}
+	}
+}
+
+// stopQueue drops the messages still in the queue, and makes it drop the ones written from now on.
+func (c *wsConnection) stopQueue() {
+	q := c.queue
+	q.mu.Lock()
+	q.stopped = true
+	q.messages = nil
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close sends the close frame once the messages queued before it have been sent, and closes the connection.
+func (c *wsConnection) close(closeCode int, message string) {
+	c.setCloseCode(closeCode)
+
+	q := c.queue
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		<-q.done
+		_ = c.conn.Close()
+		return
+	}
+	q.messages = append(q.messages, &outboundMessage{close: true, closeCode: closeCode, closeText: message})
+	depth := len(q.messages)
+	q.stopped = true
+	q.mu.Unlock()
+
+	c.notifyQueue(depth)
+	<-q.done
+}
+
+// abort closes the connection without waiting for the queued messages, which are dropped. The close frame is sent
+// once the message being written, if any, has been. Both writes are given up on after WriteTimeout, or
+// closeWriteTimeout when there is none.
+func (c *wsConnection) abort(closeCode int, message string) {
+	c.setCloseCode(closeCode)
+
+	q := c.queue
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.messages = []*outboundMessage{{close: true, closeCode: closeCode, closeText: message}}
+	q.stopped = true
+	if q.deadline.IsZero() {
+		timeout := c.WriteTimeout
+		if timeout == 0 {
+			timeout = closeWriteTimeout
+		}
+		q.deadline = time.Now().Add(timeout)
+	}
+	deadline := q.deadline
+	// ends the read loop, closing a hijacked connection only takes effect once the handler returns
+	_ = c.conn.SetReadDeadline(time.Now())
+	q.mu.Unlock()
+
+	// the writer applies the deadline to the following writes, this one ends the write it may be blocked in
+	_ = c.conn.UnderlyingConn().SetWriteDeadline(deadline)
+	c.notifyQueue(1)
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestSendQueueDropOldest(t *testing.T) {
+	c := &wsConnection{
+		Websocket:  Websocket{SendQueueSize: 2, OverflowPolicy: OverflowDropOldest},
+		queue:      newSendQueue(),
+		overflowed: map[string]struct{}{},
+	}
+
+	t.Run("drops the oldest data message", func(t *testing.T) {
+		c.write(&message{t: keepAliveMessageType})
+		c.write(&message{t: dataMessageType, id: "1"})
+		c.write(&message{t: dataMessageType, id: "2"})
+
+		require.Len(t, c.queue.messages, 2)
+		require.Equal(t, keepAliveMessageType, c.queue.messages[0].msg.t)
+		require.Equal(t, "2", c.queue.messages[1].msg.id)
+	})
+
+	t.Run("drops the new data message when the queue holds no other", func(t *testing.T) {
+		c.queue.messages = nil
+		c.write(&message{t: keepAliveMessageType})
+		c.write(&message{t: completeMessageType, id: "1"})
+		c.write(&message{t: dataMessageType, id: "2"})
+
+		require.Len(t, c.queue.messages, 2)
+		require.Equal(t, keepAliveMessageType, c.queue.messages[0].msg.t)
+		require.Equal(t, completeMessageType, c.queue.messages[1].msg.t)
+	})
+}
+
+func TestSendQueueLimits(t *testing.T) {
+	t.Run("closes the connection when protocol messages pile up", func(t *testing.T) {
+		c := &wsConnection{
+			Websocket:  Websocket{SendQueueSize: 2},
+			conn:       unreadConn(t),
+			queue:      newSendQueue(),
+			overflowed: map[string]struct{}{},
+		}
+		for i := 0; i < 5; i++ {
+			c.write(&message{t: keepAliveMessageType})
+		}
+
+		require.Len(t, c.queue.messages, 1)
+		require.True(t, c.queue.messages[0].close)
+		require.Equal(t, websocket.CloseTryAgainLater, c.queue.messages[0].closeCode)
+	})
+
+	t.Run("gives up on the writes of aborted connections", func(t *testing.T) {
+		closeWriteTimeout = 100 * time.Millisecond
+		defer func() { closeWriteTimeout = 10 * time.Second }()
+
+		ws := unreadConn(t)
+		c := &wsConnection{
+			Websocket:  Websocket{SendQueueSize: 1000, OverflowPolicy: OverflowCloseConnection},
+			conn:       ws,
+			me:         graphqlwsMessageExchanger{c: ws},
+			queue:      newSendQueue(),
+			overflowed: map[string]struct{}{},
+		}
+		go c.writeLoop()
+
+		// the results are big enough to fill the socket buffers, the writer blocks on a client that doesn't read
+		payload := []byte(fmt.Sprintf("%q", strings.Repeat("x", 256<<10)))
+		for i := 0; i < 100; i++ {
+			c.write(&message{t: dataMessageType, id: "1", payload: payload})
+		}
+		for depth := -1; depth != c.queueDepth(); {
+			depth = c.queueDepth()
+			time.Sleep(50 * time.Millisecond)
+		}
+		require.NotZero(t, c.queueDepth())
+		c.abort(websocket.CloseTryAgainLater, "send queue is full")
+
+		select {
+		case <-c.queue.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the writer is still blocked")
+		}
+	})
+}
+
+// unreadConn returns the server side of a websocket connection whose client never reads.
+func unreadConn(t *testing.T) *websocket.Conn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	conns := make(chan *websocket.Conn)
+	closed := make(chan struct{})
+	upgrader := websocket.FastHTTPUpgrader{}
+	go func() {
+		_ = fasthttp.Serve(ln, func(ctx *fasthttp.RequestCtx) {
+			_ = upgrader.Upgrade(ctx, func(ws *websocket.Conn) {
+				conns <- ws
+				<-closed
+			})
+		})
+	}()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String(), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		close(closed)
+		_ = client.Close()
+		_ = ln.Close()
+	})
+	return <-conns
+}
+
+func (c *wsConnection) queueDepth() int {
+	c.queue.mu.Lock()
+	defer c.queue.mu.Unlock()
+	return len(c.queue.messages)
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -250,7 +252,7 @@ func TestWebsocketInitFunc(t *testing.T) {
 	t.Run("can return context for request from WebsocketInitFunc", func(t *testing.T) {
 		es := &graphql.ExecutableSchemaMock{
 			ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
-				assert.Equal(t, "newvalue", ctx.Value(ckey("newkey")))
+				assert.Equal(t, "newvalue", ctx.Value("newkey"))
 				return graphql.OneShot(&graphql.Response{Data: []byte(`{"empty":"ok"}`)})
 			},
 			SchemaFunc: func() *ast.Schema {
@@ -282,6 +284,762 @@ func TestWebsocketInitFunc(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "ok", resp.Empty)
 	})
+
+	t.Run("can return context for operations from InitContextFunc", func(t *testing.T) {
+		es := &graphql.ExecutableSchemaMock{
+			ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+				assert.Equal(t, "newvalue", ctx.Value(ckey("newkey")))
+				assert.Equal(t, "token", transport.GetInitPayload(ctx).Authorization())
+				return graphql.OneShot(&graphql.Response{Data: []byte(`{"empty":"ok"}`)})
+			},
+			SchemaFunc: func() *ast.Schema {
+				return gqlparser.MustLoadSchema(&ast.Source{Input: `
+				schema { query: Query }
+				type Query {
+					empty: String
+				}
+			`})
+			},
+		}
+		h := handler.New(es)
+
+		var closeCtx context.Context
+		closed := make(chan struct{})
+		h.AddTransport(transport.Websocket{
+			InitContextFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+				return context.WithValue(ctx, ckey("newkey"), "newvalue"), nil, nil
+			},
+			CloseFunc: func(ctx context.Context, closeCode int) {
+				closeCtx = ctx
+				close(closed)
+			},
+		})
+
+		c := client.New(h.Handler())
+
+		socket := c.WebsocketWithPayload("{ empty } ", map[string]interface{}{"Authorization": "token"})
+		var resp struct {
+			Empty string
+		}
+		err := socket.Next(&resp)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", resp.Empty)
+
+		require.NoError(t, socket.Close())
+		<-closed
+		assert.Equal(t, "newvalue", closeCtx.Value(ckey("newkey")))
+	})
+
+	t.Run("send the payload returned by InitContextFunc in connection_ack", func(t *testing.T) {
+		handler := testserver.New()
+		handler.AddTransport(transport.Websocket{
+			InitContextFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+				return ctx, &transport.InitPayload{"user": "alice"}, nil
+			},
+		})
+
+		ln := startServerOnPort(t, 1234, handler.Handler())
+		defer ln.Close()
+
+		url := ln.Addr().String()
+
+		t.Run("graphql-ws", func(t *testing.T) {
+			c := wsConnect(url)
+			defer c.Close()
+
+			require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+
+			msg := readOp(c)
+			assert.Equal(t, connectionAckMsg, msg.Type)
+			assert.Equal(t, `{"user":"alice"}`, string(msg.Payload))
+		})
+
+		t.Run("graphql-transport-ws", func(t *testing.T) {
+			c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+			defer c.Close()
+
+			require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+
+			msg := readOp(c)
+			assert.Equal(t, graphqltransportwsConnectionAckMsg, msg.Type)
+			assert.Equal(t, `{"user":"alice"}`, string(msg.Payload))
+		})
+	})
+
+	t.Run("reject connection if InitContextFunc returns an error", func(t *testing.T) {
+		handler := testserver.New()
+		handler.AddTransport(transport.Websocket{
+			InitContextFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+				return ctx, nil, errors.New("invalid token")
+			},
+		})
+
+		ln := startServerOnPort(t, 1234, handler.Handler())
+		defer ln.Close()
+
+		c := wsConnectWithSubprocotol(ln.Addr().String(), graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, &websocket.CloseError{Code: 4403, Text: "invalid token"}, err)
+	})
+
+	t.Run("the hooks get a copy of the request", func(t *testing.T) {
+		initFuncClient := make(chan string, 1)
+		initContextFuncClient := make(chan string, 1)
+		handler := testserver.New()
+		handler.AddTransport(transport.Websocket{
+			InitFunc: func(ctx *fasthttp.RequestCtx, initPayload transport.InitPayload) (*fasthttp.RequestCtx, error) {
+				initFuncClient <- string(ctx.Request.Header.Peek("X-Client"))
+				return ctx, nil
+			},
+			InitContextFunc: func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+				// the request the connection was upgraded from is serving other clients by now
+				assert.Nil(t, graphql.GetRequestCtx(ctx))
+				initContextFuncClient <- string(graphql.GetRequestInfo(ctx).Header.Peek("X-Client"))
+				return ctx, nil, nil
+			},
+		})
+
+		ln := startServerOnPort(t, 1234, handler.Handler())
+		defer ln.Close()
+
+		c, resp, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String(), http.Header{"X-Client": {"alice"}})
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, "alice", <-initFuncClient)
+		assert.Equal(t, "alice", <-initContextFuncClient)
+	})
+}
+
+func TestWebsocketGraphqltransportwsSubprotocol(t *testing.T) {
+	handler := testserver.New()
+	handler.AddTransport(transport.Websocket{
+		InitTimeout: 100 * time.Millisecond,
+	})
+
+	ln := startServerOnPort(t, 1234, handler.Handler())
+	defer ln.Close()
+
+	url := ln.Addr().String()
+
+	t.Run("server negotiates the subprotocol", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		assert.Equal(t, graphqltransportwsSubprotocol, c.Subprotocol())
+	})
+
+	t.Run("server acks init without keep alive", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsPingMsg}))
+		assert.Equal(t, graphqltransportwsPongMsg, readOp(c).Type)
+	})
+
+	t.Run("server closes when init times out", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, 4408, err.(*websocket.CloseError).Code)
+	})
+
+	t.Run("client must send valid json", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		writeRaw(c, "hello")
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, 4400, err.(*websocket.CloseError).Code)
+	})
+
+	t.Run("client must init before subscribing", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    graphqltransportwsSubscribeMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+		}))
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, 4401, err.(*websocket.CloseError).Code)
+	})
+
+	t.Run("client can only init once", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, 4429, err.(*websocket.CloseError).Code)
+	})
+
+	t.Run("client gets parse errors", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    graphqltransportwsSubscribeMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "!"}`),
+		}))
+
+		msg := readOp(c)
+		assert.Equal(t, graphqltransportwsErrorMsg, msg.Type)
+		assert.Equal(t, `[{"message":"Unexpected !","locations":[{"line":1,"column":1}],"extensions":{"code":"GRAPHQL_PARSE_FAILED"}}]`, string(msg.Payload))
+	})
+
+	t.Run("client can receive data", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(url, graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    graphqltransportwsSubscribeMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+		}))
+
+		handler.SendNextSubscriptionMessage()
+		msg := readOp(c)
+		require.Equal(t, graphqltransportwsNextMsg, msg.Type, string(msg.Payload))
+		require.Equal(t, "test_1", msg.ID, string(msg.Payload))
+		require.Equal(t, `{"data":{"name":"test"}}`, string(msg.Payload))
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    graphqltransportwsSubscribeMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+		}))
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, 4409, err.(*websocket.CloseError).Code)
+	})
+}
+
+func TestWebsocketGraphqltransportwsClient(t *testing.T) {
+	handler := testserver.New()
+	handler.AddTransport(transport.Websocket{})
+
+	c := client.New(handler.Handler())
+
+	socket := c.Websocket("subscription { name }", client.WebsocketSubprotocol(graphqltransportwsSubprotocol))
+	defer socket.Close()
+
+	handler.SendNextSubscriptionMessage()
+	var resp struct {
+		Name string
+	}
+	require.NoError(t, socket.Next(&resp))
+	assert.Equal(t, "test", resp.Name)
+}
+
+func TestWebsocketLifecycleHooks(t *testing.T) {
+	closed := make(chan int, 1)
+	started := make(chan string, 1)
+	completed := make(chan string, 1)
+
+	h := testserver.New()
+	h.AddTransport(transport.Websocket{
+		CloseFunc: func(ctx context.Context, closeCode int) {
+			closed <- closeCode
+		},
+		OnOperationStart: func(ctx context.Context, id string) {
+			started <- id + " " + graphql.GetOperationContext(ctx).Operation.Name
+		},
+		OnOperationComplete: func(ctx context.Context, id string) {
+			completed <- id
+		},
+	})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	t.Run("operations start and complete", func(t *testing.T) {
+		c := wsConnect(ln.Addr().String())
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    startMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription Names { name }"}`),
+		}))
+		assert.Equal(t, "test_1 Names", <-started)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: stopMsg, ID: "test_1"}))
+		assert.Equal(t, "test_1", <-completed)
+		assert.Equal(t, completeMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionTerminateMsg}))
+		assert.Equal(t, websocket.CloseNormalClosure, <-closed)
+	})
+
+	t.Run("close code sent by the client", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(ln.Addr().String(), graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "bye")))
+		assert.Equal(t, websocket.CloseGoingAway, <-closed)
+	})
+
+	t.Run("close code sent by the server", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(ln.Addr().String(), graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		writeRaw(c, "hello")
+		assert.Equal(t, 4400, <-closed)
+	})
+
+	t.Run("operations complete when the connection is lost", func(t *testing.T) {
+		c := wsConnect(ln.Addr().String())
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    startMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+		}))
+		assert.Equal(t, "test_1 ", <-started)
+
+		require.NoError(t, c.UnderlyingConn().Close())
+		assert.Equal(t, websocket.CloseAbnormalClosure, <-closed)
+		assert.Equal(t, "test_1", <-completed)
+	})
+}
+
+func TestWebsocketOperationLimits(t *testing.T) {
+	h := testserver.New()
+	h.AddTransport(transport.Websocket{MaxActiveOperations: 1})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	t.Run("graphql-ws rejects operations past the limit", func(t *testing.T) {
+		c := wsConnect(ln.Addr().String())
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+		subscribe(t, c, startMsg, "test_1")
+		subscribe(t, c, startMsg, "test_2")
+
+		msg := readOp(c)
+		assert.Equal(t, errorMsg, msg.Type)
+		assert.Equal(t, "test_2", msg.ID)
+		assert.Equal(t, `[{"message":"too many active operations, at most 1 are allowed"}]`, string(msg.Payload))
+		msg = readOp(c)
+		assert.Equal(t, completeMsg, msg.Type)
+		assert.Equal(t, "test_2", msg.ID)
+
+		// the running operation is left alone
+		h.SendNextSubscriptionMessage()
+		msg = readOp(c)
+		assert.Equal(t, dataMsg, msg.Type)
+		assert.Equal(t, "test_1", msg.ID)
+	})
+
+	t.Run("graphql-ws rejects duplicate ids", func(t *testing.T) {
+		c := wsConnect(ln.Addr().String())
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+		subscribe(t, c, startMsg, "test_1")
+		subscribe(t, c, startMsg, "test_1")
+
+		msg := readOp(c)
+		assert.Equal(t, errorMsg, msg.Type)
+		assert.Equal(t, "test_1", msg.ID)
+		assert.Equal(t, `[{"message":"subscriber for test_1 already exists"}]`, string(msg.Payload))
+
+		h.SendNextSubscriptionMessage()
+		msg = readOp(c)
+		assert.Equal(t, dataMsg, msg.Type)
+		assert.Equal(t, "test_1", msg.ID)
+	})
+
+	t.Run("graphql-transport-ws rejects operations past the limit", func(t *testing.T) {
+		c := wsConnectWithSubprocotol(ln.Addr().String(), graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		subscribe(t, c, graphqltransportwsSubscribeMsg, "test_1")
+		subscribe(t, c, graphqltransportwsSubscribeMsg, "test_2")
+
+		msg := readOp(c)
+		assert.Equal(t, graphqltransportwsErrorMsg, msg.Type)
+		assert.Equal(t, "test_2", msg.ID)
+		assert.Equal(t, `[{"message":"too many active operations, at most 1 are allowed"}]`, string(msg.Payload))
+
+		h.SendNextSubscriptionMessage()
+		msg = readOp(c)
+		assert.Equal(t, graphqltransportwsNextMsg, msg.Type)
+		assert.Equal(t, "test_1", msg.ID)
+	})
+}
+
+func TestWebsocketSendQueue(t *testing.T) {
+	// the results are big enough to fill the socket buffers, so they pile up in the send queue of a client that
+	// doesn't read
+	payload := strings.Repeat("x", 256<<10)
+	var produced chan struct{}
+	newServer := func(ws transport.Websocket) *handler.Server {
+		produced = make(chan struct{})
+		h := handler.New(&graphql.ExecutableSchemaMock{
+			ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+				sent := 0
+				return func(ctx context.Context) *graphql.Response {
+					if sent == 100 {
+						close(produced)
+						return nil
+					}
+					sent++
+					return &graphql.Response{Data: []byte(fmt.Sprintf(`{"name":%q}`, payload))}
+				}
+			},
+			SchemaFunc: func() *ast.Schema {
+				return gqlparser.MustLoadSchema(&ast.Source{Input: `
+					type Query { name: String! }
+					type Subscription { name: String! }
+				`})
+			},
+		})
+		h.AddTransport(ws)
+		return h
+	}
+
+	// readAll reads the messages of a client that fell behind, until the operation ends or the connection is closed
+	readAll := func(c *websocket.Conn) (messages []operationMessage, closeErr *websocket.CloseError) {
+		<-produced
+		for {
+			var msg operationMessage
+			if err := c.ReadJSON(&msg); err != nil {
+				closeErr, _ = err.(*websocket.CloseError)
+				return messages, closeErr
+			}
+			messages = append(messages, msg)
+			if msg.Type == completeMsg {
+				return messages, nil
+			}
+		}
+	}
+
+	connect := func(t *testing.T, url string) *websocket.Conn {
+		c := wsConnect(url)
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+		subscribe(t, c, startMsg, "test_1")
+		return c
+	}
+
+	t.Run("close the operation", func(t *testing.T) {
+		var maxDepth int32
+		h := newServer(transport.Websocket{
+			SendQueueSize: 2,
+			OnQueueDepth: func(ctx context.Context, depth int) {
+				if int32(depth) > atomic.LoadInt32(&maxDepth) {
+					atomic.StoreInt32(&maxDepth, int32(depth))
+				}
+			},
+		})
+		ln := startServerOnPort(t, 1234, h.Handler())
+		defer ln.Close()
+
+		c := connect(t, ln.Addr().String())
+		defer c.Close()
+
+		messages, closeErr := readAll(c)
+		require.Nil(t, closeErr)
+		require.Less(t, len(messages), 100)
+
+		errMsg := messages[len(messages)-2]
+		assert.Equal(t, errorMsg, errMsg.Type)
+		assert.Equal(t, `[{"message":"the client doesn't read the results fast enough, the operation has been stopped"}]`, string(errMsg.Payload))
+		assert.Equal(t, completeMsg, messages[len(messages)-1].Type)
+		assert.GreaterOrEqual(t, atomic.LoadInt32(&maxDepth), int32(2))
+	})
+
+	t.Run("drop the oldest results", func(t *testing.T) {
+		h := newServer(transport.Websocket{SendQueueSize: 2, OverflowPolicy: transport.OverflowDropOldest})
+		ln := startServerOnPort(t, 1234, h.Handler())
+		defer ln.Close()
+
+		c := connect(t, ln.Addr().String())
+		defer c.Close()
+
+		messages, closeErr := readAll(c)
+		require.Nil(t, closeErr)
+		require.Less(t, len(messages), 101)
+		for _, msg := range messages[:len(messages)-1] {
+			assert.Equal(t, dataMsg, msg.Type)
+		}
+		assert.Equal(t, completeMsg, messages[len(messages)-1].Type)
+	})
+
+	t.Run("close the connection", func(t *testing.T) {
+		h := newServer(transport.Websocket{SendQueueSize: 2, OverflowPolicy: transport.OverflowCloseConnection})
+		ln := startServerOnPort(t, 1234, h.Handler())
+		defer ln.Close()
+
+		c := connect(t, ln.Addr().String())
+		defer c.Close()
+
+		_, closeErr := readAll(c)
+		require.NotNil(t, closeErr)
+		assert.Equal(t, websocket.CloseTryAgainLater, closeErr.Code)
+	})
+}
+
+func TestWebsocketTimeouts(t *testing.T) {
+	closed := make(chan int, 1)
+	h := testserver.New()
+	h.AddTransport(transport.Websocket{
+		PingPongInterval: 50 * time.Millisecond,
+		ReadIdleTimeout:  200 * time.Millisecond,
+		CloseFunc: func(ctx context.Context, closeCode int) {
+			closed <- closeCode
+		},
+	})
+
+	ln := startServerOnPort(t, 1234, h.Handler())
+	defer ln.Close()
+
+	url := ln.Addr().String()
+
+	init := func(t *testing.T, c *websocket.Conn) {
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+	}
+
+	t.Run("clients answering pings stay connected", func(t *testing.T) {
+		c := wsConnect(url)
+		defer c.Close()
+		init(t, c)
+
+		pinged := make(chan struct{}, 10)
+		c.SetPingHandler(func(appData string) error {
+			pinged <- struct{}{}
+			return c.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(time.Second))
+		})
+		go func() {
+			for {
+				if _, _, err := c.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		// the pongs keep the connection from being idle too
+		time.Sleep(500 * time.Millisecond)
+		assert.GreaterOrEqual(t, len(pinged), 3)
+		select {
+		case code := <-closed:
+			t.Fatalf("connection closed with %d", code)
+		default:
+		}
+	})
+	<-closed
+
+	t.Run("clients not answering pings are closed", func(t *testing.T) {
+		c := wsConnect(url)
+		defer c.Close()
+		init(t, c)
+
+		c.SetPingHandler(func(string) error { return nil })
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, &websocket.CloseError{Code: transport.CloseCodePongTimeout, Text: "pong timeout"}, err)
+		assert.Equal(t, transport.CloseCodePongTimeout, <-closed)
+	})
+
+	t.Run("idle clients are closed", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.Websocket{
+			ReadIdleTimeout: 100 * time.Millisecond,
+			CloseFunc: func(ctx context.Context, closeCode int) {
+				closed <- closeCode
+			},
+		})
+
+		ln := startServerOnPort(t, 1235, h.Handler())
+		defer ln.Close()
+
+		c := wsConnect(ln.Addr().String())
+		defer c.Close()
+		init(t, c)
+
+		start := time.Now()
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, &websocket.CloseError{Code: transport.CloseCodeReadIdleTimeout, Text: "read idle timeout"}, err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Millisecond))
+		assert.Equal(t, transport.CloseCodeReadIdleTimeout, <-closed)
+	})
+}
+
+func TestWebsocketShutdown(t *testing.T) {
+	shutdown := func(t *testing.T, h *testserver.TestServer) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			errs <- h.Shutdown(ctx)
+		}()
+		return errs
+	}
+
+	t.Run("graphql-ws subscriptions are completed", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.Websocket{})
+		ln := startServerOnPort(t, 1234, h.Handler())
+		defer ln.Close()
+
+		c := wsConnect(ln.Addr().String())
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    startMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+		}))
+		h.SendNextSubscriptionMessage()
+		assert.Equal(t, dataMsg, readOp(c).Type)
+
+		errs := shutdown(t, h)
+
+		msg := readOp(c)
+		assert.Equal(t, completeMsg, msg.Type)
+		assert.Equal(t, "test_1", msg.ID)
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, websocket.CloseGoingAway, err.(*websocket.CloseError).Code)
+		require.NoError(t, <-errs)
+
+		_, _, err = websocket.DefaultDialer.Dial("ws://"+ln.Addr().String(), nil)
+		assert.Equal(t, websocket.ErrBadHandshake, err)
+	})
+
+	t.Run("graphql-transport-ws subscriptions are completed", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.Websocket{})
+		ln := startServerOnPort(t, 1234, h.Handler())
+		defer ln.Close()
+
+		c := wsConnectWithSubprocotol(ln.Addr().String(), graphqltransportwsSubprotocol)
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: graphqltransportwsConnectionInitMsg}))
+		assert.Equal(t, graphqltransportwsConnectionAckMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    graphqltransportwsSubscribeMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+		}))
+		h.SendNextSubscriptionMessage()
+		assert.Equal(t, graphqltransportwsNextMsg, readOp(c).Type)
+
+		errs := shutdown(t, h)
+
+		msg := readOp(c)
+		assert.Equal(t, graphqltransportwsCompleteMsg, msg.Type)
+		assert.Equal(t, "test_1", msg.ID)
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, websocket.CloseGoingAway, err.(*websocket.CloseError).Code)
+		require.NoError(t, <-errs)
+	})
+
+	t.Run("queries in flight are waited for", func(t *testing.T) {
+		h := testserver.New()
+		h.AddTransport(transport.Websocket{})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		h.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+			close(started)
+			<-release
+			return next(ctx)
+		})
+
+		ln := startServerOnPort(t, 1234, h.Handler())
+		defer ln.Close()
+
+		c := wsConnect(ln.Addr().String())
+		defer c.Close()
+
+		require.NoError(t, c.WriteJSON(&operationMessage{Type: connectionInitMsg}))
+		assert.Equal(t, connectionAckMsg, readOp(c).Type)
+		assert.Equal(t, connectionKeepAliveMsg, readOp(c).Type)
+
+		require.NoError(t, c.WriteJSON(&operationMessage{
+			Type:    startMsg,
+			ID:      "test_1",
+			Payload: json.RawMessage(`{"query": "{ name }"}`),
+		}))
+		<-started
+
+		errs := shutdown(t, h)
+		select {
+		case err := <-errs:
+			t.Fatalf("shutdown returned before the query was over: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		msg := readOp(c)
+		assert.Equal(t, dataMsg, msg.Type)
+		assert.Equal(t, `{"data":{"name":"test"}}`, string(msg.Payload))
+		assert.Equal(t, completeMsg, readOp(c).Type)
+
+		_, _, err := c.ReadMessage()
+		assert.Equal(t, websocket.CloseGoingAway, err.(*websocket.CloseError).Code)
+		require.NoError(t, <-errs)
+	})
 }
 
 func startServerOnPort(t *testing.T, port int, h fasthttp.RequestHandler) net.Listener {
@@ -308,6 +1066,34 @@ func wsConnect(url string) *websocket.Conn {
 	return c
 }
 
+func wsConnectWithSubprocotol(url, subprocotol string) *websocket.Conn {
+	h := make(http.Header)
+	if subprocotol != "" {
+		h.Add("Sec-WebSocket-Protocol", subprocotol)
+	}
+
+	url = strings.Replace(url, "http://", "ws://", -1)
+	if !strings.HasPrefix(url, "ws://") {
+		url = "ws://" + url
+	}
+
+	c, resp, err := websocket.DefaultDialer.Dial(url, h)
+	if err != nil {
+		panic(err)
+	}
+	_ = resp.Body.Close()
+
+	return c
+}
+
+func subscribe(t *testing.T, conn *websocket.Conn, msgType string, id string) {
+	require.NoError(t, conn.WriteJSON(&operationMessage{
+		Type:    msgType,
+		ID:      id,
+		Payload: json.RawMessage(`{"query": "subscription { name }"}`),
+	}))
+}
+
 func writeRaw(conn *websocket.Conn, msg string) {
 	if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
 		panic(err)
@@ -337,6 +1123,19 @@ const (
 	connectionKeepAliveMsg = "ka"                   // Server -> Client
 )
 
+const (
+	graphqltransportwsSubprotocol = "graphql-transport-ws"
+
+	graphqltransportwsConnectionInitMsg = "connection_init"
+	graphqltransportwsConnectionAckMsg  = "connection_ack"
+	graphqltransportwsSubscribeMsg      = "subscribe"
+	graphqltransportwsNextMsg           = "next"
+	graphqltransportwsErrorMsg          = "error"
+	graphqltransportwsCompleteMsg       = "complete"
+	graphqltransportwsPingMsg           = "ping"
+	graphqltransportwsPongMsg           = "pong"
+)
+
 type operationMessage struct {
 	Payload json.RawMessage `json:"payload,omitempty"`
 	ID      string          `json:"id,omitempty"`
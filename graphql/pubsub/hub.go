@@ -0,0 +1,135 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// OverflowPolicy decides what happens to a message published to a subscriber whose buffer is full. Publish never
+// waits for slow subscribers.
+type OverflowPolicy int
+
+const (
+	// OverflowDropMessage drops the message for the subscriber, which keeps receiving the next ones.
+	OverflowDropMessage OverflowPolicy = iota
+
+	// OverflowCloseSubscriber closes the channel of the subscriber, which ends its subscription.
+	OverflowCloseSubscriber
+)
+
+// Hub is a Broker keeping its subscribers in memory, it only reaches the subscribers of the process it runs in.
+type Hub struct {
+	bufferSize int
+	overflow   OverflowPolicy
+
+	mu     sync.RWMutex
+	topics map[string]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	mu     sync.Mutex
+	ch     chan interface{}
+	closed bool
+	done   chan struct{}
+}
+
+var _ Broker = &Hub{}
+
+// New returns a Hub whose subscribers can each have bufferSize messages waiting for them, 16 when it isn't positive.
+// overflow decides what happens to the messages published to a subscriber whose buffer is full.
+func New(bufferSize int, overflow OverflowPolicy) *Hub {
+	// subscribers without a buffer would miss the messages published while they aren't waiting for one
+	if bufferSize <= 0 {
+		bufferSize = 16
+	}
+	return &Hub{
+		bufferSize: bufferSize,
+		overflow:   overflow,
+		topics:     map[string]map[*subscriber]struct{}{},
+	}
+}
+
+// Publish sends msg to the subscribers of topic. The error is always nil.
+func (h *Hub) Publish(ctx context.Context, topic string, msg interface{}) error {
+	h.mu.RLock()
+	subscribers := make([]*subscriber, 0, len(h.topics[topic]))
+	for s := range h.topics[topic] {
+		subscribers = append(subscribers, s)
+	}
+	h.mu.RUnlock()
+
+	for _, s := range subscribers {
+		if !s.send(msg) && h.overflow == OverflowCloseSubscriber {
+			h.unsubscribe(topic, s)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel receiving the messages published on topic from now on, it is closed once ctx is done
+// or, with OverflowCloseSubscriber, once the subscriber falls behind. The error is always nil.
+func (h *Hub) Subscribe(ctx context.Context, topic string) (<-chan interface{}, error) {
+	s := &subscriber{ch: make(chan interface{}, h.bufferSize), done: make(chan struct{})}
+
+	h.mu.Lock()
+	if h.topics[topic] == nil {
+		h.topics[topic] = map[*subscriber]struct{}{}
+	}
+	h.topics[topic][s] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.unsubscribe(topic, s)
+		case <-s.done:
+		}
+	}()
+
+	return s.ch, nil
+}
+
+// Subscribers returns the number of subscribers of topic.
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
+
+func (h *Hub) unsubscribe(topic string, s *subscriber) {
+	h.mu.Lock()
+	delete(h.topics[topic], s)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+	h.mu.Unlock()
+
+	s.close()
+}
+
+// send queues msg for the subscriber, it reports false when the buffer of the subscriber is full.
+func (s *subscriber) send(msg interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return true
+	}
+	select {
+	case s.ch <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+		close(s.done)
+	}
+}
@@ -0,0 +1,186 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sujamess/fastgql/graphql/pubsub"
+)
+
+func TestHub(t *testing.T) {
+	t.Run("subscribers receive the messages of their topic", func(t *testing.T) {
+		hub := pubsub.New(1, pubsub.OverflowDropMessage)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		room1, err := hub.Subscribe(ctx, "room1")
+		require.NoError(t, err)
+		room2, err := hub.Subscribe(ctx, "room2")
+		require.NoError(t, err)
+
+		require.NoError(t, hub.Publish(ctx, "room1", "hello"))
+		require.NoError(t, hub.Publish(ctx, "room2", "bye"))
+
+		assert.Equal(t, "hello", <-room1)
+		assert.Equal(t, "bye", <-room2)
+	})
+
+	t.Run("the channel is closed when the context is cancelled", func(t *testing.T) {
+		hub := pubsub.New(1, pubsub.OverflowDropMessage)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		sub, err := hub.Subscribe(ctx, "room")
+		require.NoError(t, err)
+		assert.Equal(t, 1, hub.Subscribers("room"))
+
+		cancel()
+		_, ok := <-sub
+		assert.False(t, ok)
+		assert.Equal(t, 0, hub.Subscribers("room"))
+	})
+
+	t.Run("slow subscribers miss the messages over their buffer", func(t *testing.T) {
+		hub := pubsub.New(2, pubsub.OverflowDropMessage)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		slow, err := hub.Subscribe(ctx, "room")
+		require.NoError(t, err)
+		fast, err := hub.Subscribe(ctx, "room")
+		require.NoError(t, err)
+
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, hub.Publish(ctx, "room", i))
+			assert.Equal(t, i, <-fast)
+		}
+
+		assert.Equal(t, 1, <-slow)
+		assert.Equal(t, 2, <-slow)
+		require.NoError(t, hub.Publish(ctx, "room", 4))
+		assert.Equal(t, 4, <-slow)
+		assert.Equal(t, 2, hub.Subscribers("room"))
+	})
+
+	t.Run("slow subscribers are closed", func(t *testing.T) {
+		hub := pubsub.New(1, pubsub.OverflowCloseSubscriber)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		slow, err := hub.Subscribe(ctx, "room")
+		require.NoError(t, err)
+		fast, err := hub.Subscribe(ctx, "room")
+		require.NoError(t, err)
+
+		for i := 1; i <= 2; i++ {
+			require.NoError(t, hub.Publish(ctx, "room", i))
+			assert.Equal(t, i, <-fast)
+		}
+
+		assert.Equal(t, 1, <-slow)
+		_, ok := <-slow
+		assert.False(t, ok)
+		assert.Equal(t, 1, hub.Subscribers("room"))
+	})
+
+	t.Run("subscribers get a buffer by default", func(t *testing.T) {
+		hub := pubsub.New(0, pubsub.OverflowDropMessage)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sub, err := hub.Subscribe(ctx, "room")
+		require.NoError(t, err)
+		require.NoError(t, hub.Publish(ctx, "room", "hello"))
+		assert.Equal(t, "hello", <-sub)
+	})
+}
+
+type message struct {
+	text string
+}
+
+// messageTopic is the typed wrapper of a topic the package documentation recommends.
+type messageTopic struct {
+	pubsub.Topic
+}
+
+func (t messageTopic) Subscribe(ctx context.Context, room string) (<-chan *message, error) {
+	messages := make(chan *message, 1)
+	err := t.Topic.Subscribe(ctx, room, func(msg interface{}, done <-chan struct{}) bool {
+		select {
+		case messages <- msg.(*message):
+			return true
+		case <-done:
+			return false
+		}
+	}, func() { close(messages) })
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func TestTopic(t *testing.T) {
+	hub := pubsub.New(1, pubsub.OverflowDropMessage)
+	topic := messageTopic{pubsub.Topic{Broker: hub, Prefix: "room:"}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	messages, err := topic.Subscribe(ctx, "general")
+	require.NoError(t, err)
+	assert.Equal(t, 1, hub.Subscribers("room:general"))
+
+	require.NoError(t, topic.Publish(ctx, "general", &message{text: "hello"}))
+	assert.Equal(t, "hello", (<-messages).text)
+
+	cancel()
+	for range messages {
+	}
+}
+
+func TestForward(t *testing.T) {
+	t.Run("ends once the subscription is closed", func(t *testing.T) {
+		sub := make(chan interface{}, 2)
+		sub <- "hello"
+		sub <- "bye"
+		close(sub)
+
+		received := make(chan interface{}, 2)
+		ended := make(chan struct{})
+		pubsub.Forward(context.Background(), sub, func(msg interface{}, done <-chan struct{}) bool {
+			received <- msg
+			return true
+		}, func() { close(ended) })
+
+		<-ended
+		assert.Equal(t, "hello", <-received)
+		assert.Equal(t, "bye", <-received)
+	})
+
+	t.Run("ends once send gives up", func(t *testing.T) {
+		sub := make(chan interface{}, 2)
+		sub <- "hello"
+		sub <- "bye"
+
+		sent := 0
+		ended := make(chan struct{})
+		pubsub.Forward(context.Background(), sub, func(msg interface{}, done <-chan struct{}) bool {
+			sent++
+			return false
+		}, func() { close(ended) })
+
+		<-ended
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("ends once the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ended := make(chan struct{})
+		pubsub.Forward(ctx, make(chan interface{}), func(msg interface{}, done <-chan struct{}) bool {
+			return true
+		}, func() { close(ended) })
+
+		cancel()
+		<-ended
+	})
+}
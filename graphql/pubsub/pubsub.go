@@ -0,0 +1,85 @@
+// Package pubsub routes the events published by mutations, or anything else, to subscription resolvers.
+package pubsub
+
+import (
+	"context"
+)
+
+// Broker delivers the messages published on a topic to the subscribers of the topic. Hub keeps subscribers in
+// memory, other implementations can relay the messages through an external system to reach several servers.
+
+type Broker interface {
+	// Publish sends msg to the subscribers of topic.
+	Publish(ctx context.Context, topic string, msg interface{}) error
+
+	// Subscribe returns a channel receiving the messages published on topic from now on. The channel is closed once
+	// ctx is done.
+	Subscribe(ctx context.Context, topic string) (<-chan interface{}, error)
+}
+
+// SendFunc hands msg to a subscription resolver, usually by converting it and sending it on the typed channel the
+// resolver returned. It gives up once done is closed, and reports whether msg was handed over.
+type SendFunc func(msg interface{}, done <-chan struct{}) bool
+
+// Forward calls send with the messages received on sub from a goroutine, until sub is closed, ctx is done or send
+// gives up, and then calls end, which usually closes the typed channel.
+func Forward(ctx context.Context, sub <-chan interface{}, send SendFunc, end func()) {
+	go func() {
+		defer end()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-sub:
+				if !ok || !send(msg, ctx.Done()) {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Topic is a family of topics of a Broker carrying one type of message, each named by Prefix followed by a key such as
+// the id of a chat room. Messages are delivered as interface{} values, resolvers are better off with a wrapper
+// embedding Topic, whose Subscribe returns the message type of the topic:
+//
+//	type messageTopic struct {
+//		pubsub.Topic
+//	}
+//
+//	func (t messageTopic) Subscribe(ctx context.Context, room string) (<-chan *Message, error) {
+//		messages := make(chan *Message, 1)
+//		err := t.Topic.Subscribe(ctx, room, func(msg interface{}, done <-chan struct{}) bool {
+//			select {
+//			case messages <- msg.(*Message):
+//				return true
+//			case <-done:
+//				return false
+//			}
+//		}, func() { close(messages) })
+//		if err != nil {
+//			return nil, err
+//		}
+//		return messages, nil
+//	}
+type Topic struct {
+	Broker Broker
+	Prefix string
+}
+
+// Publish sends msg to the subscribers of the topic of key.
+func (t Topic) Publish(ctx context.Context, key string, msg interface{}) error {
+	return t.Broker.Publish(ctx, t.Prefix+key, msg)
+}
+
+// Subscribe forwards the messages published on the topic of key to send, as Forward does, from now on. end isn't
+// called when subscribing fails.
+func (t Topic) Subscribe(ctx context.Context, key string, send SendFunc, end func()) error {
+	sub, err := t.Broker.Subscribe(ctx, t.Prefix+key)
+	if err != nil {
+		return err
+	}
+	Forward(ctx, sub, send, end)
+	return nil
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	requestCtx     key = "request_ctx"
+	requestInfoCtx key = "request_info"
+)
+
+// servedRequest is the request served by a handler.Server, along with the lock serializing the changes the
+// operations of a batch, which run concurrently, make to it.
+type servedRequest struct {
+	ctx *fasthttp.RequestCtx
+	mu  sync.Mutex
+}
+
+// RequestInfo is what operations can read of the request they are served by, once the request itself is gone. The
+// websocket transport hijacks the connection of its request, which fasthttp recycles for other requests, so the
+// operations of a websocket connection only get a copy of it.
+type RequestInfo struct {
+	// RemoteIP is the IP of the client
+	RemoteIP net.IP
+
+	// Header holds the headers of the request
+	Header *fasthttp.RequestHeader
+}
+
+// WithRequestCtx makes ctx reachable from the contexts the transports derive from it, see GetRequestCtx.
+func WithRequestCtx(ctx *fasthttp.RequestCtx) {
+	ctx.SetUserValue(string(requestCtx), &servedRequest{ctx: ctx})
+}
+
+// CopyRequestInfo copies what operations can read of the request of ctx, for the transports serving operations once
+// the request has been recycled. Pass it to WithRequestInfo along with the contexts of the operations.
+func CopyRequestInfo(ctx *fasthttp.RequestCtx) *RequestInfo {
+	info := &RequestInfo{
+		RemoteIP: append(net.IP(nil), ctx.RemoteIP()...),
+		Header:   &fasthttp.RequestHeader{},
+	}
+	ctx.Request.Header.CopyTo(info.Header)
+	return info
+}
+
+// WithRequestInfo serves the operations of ctx with info rather than with a request, GetRequestCtx returns nil for
+// them as they have no response to change.
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoCtx, info)
+}
+
+// GetRequestInfo returns what the operations served with ctx can read of their request, or nil when ctx isn't served
+// by a handler.Server. Unlike GetRequestCtx, it is available to the operations of websocket connections.
+func GetRequestInfo(ctx context.Context) *RequestInfo {
+	if info, ok := ctx.Value(requestInfoCtx).(*RequestInfo); ok {
+		return info
+	}
+	if r := getServedRequest(ctx); r != nil {
+		return &RequestInfo{RemoteIP: r.ctx.RemoteIP(), Header: &r.ctx.Request.Header}
+	}
+	return nil
+}
+
+// GetRequestCtx returns the request being served with ctx, or nil when ctx isn't served by a handler.Server or when
+// its operation outlives the request, like the ones of websocket connections. Extensions can use it to read the
+// request, use UpdateRequestCtx to change the response.
+func GetRequestCtx(ctx context.Context) *fasthttp.RequestCtx {
+	if r := getServedRequest(ctx); r != nil {
+		return r.ctx
+	}
+	return nil
+}
+
+// UpdateRequestCtx calls f with the request being served with ctx, so it can change its response, like its headers.
+// The calls are serialized, as the operations of a batch share the request. It does nothing when GetRequestCtx
+// returns nil.
+func UpdateRequestCtx(ctx context.Context, f func(rctx *fasthttp.RequestCtx)) {
+	if r := getServedRequest(ctx); r != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		f(r.ctx)
+	}
+}
+
+func getServedRequest(ctx context.Context) *servedRequest {
+	// the request of the operations served with a copy of it may be serving another client by now
+	if _, ok := ctx.Value(requestInfoCtx).(*RequestInfo); ok {
+		return nil
+	}
+	r, _ := ctx.Value(string(requestCtx)).(*servedRequest)
+	return r
+}
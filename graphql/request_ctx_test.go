@@ -0,0 +1,39 @@
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestGetRequestInfo(t *testing.T) {
+	var rctx fasthttp.RequestCtx
+	rctx.Request.Header.Set("X-Api-Key", "key")
+	WithRequestCtx(&rctx)
+
+	t.Run("with the served request", func(t *testing.T) {
+		info := GetRequestInfo(&rctx)
+		require.NotNil(t, info)
+		require.Equal(t, "key", string(info.Header.Peek("X-Api-Key")))
+		require.Equal(t, &rctx, GetRequestCtx(&rctx))
+	})
+
+	t.Run("with a copy of the request", func(t *testing.T) {
+		ctx := WithRequestInfo(&rctx, CopyRequestInfo(&rctx))
+		rctx.Request.Header.Set("X-Api-Key", "another client")
+
+		require.Equal(t, "key", string(GetRequestInfo(ctx).Header.Peek("X-Api-Key")))
+		require.Nil(t, GetRequestCtx(ctx))
+
+		updated := false
+		UpdateRequestCtx(ctx, func(rctx *fasthttp.RequestCtx) { updated = true })
+		require.False(t, updated)
+	})
+
+	t.Run("without a request", func(t *testing.T) {
+		require.Nil(t, GetRequestInfo(context.Background()))
+		require.Nil(t, GetRequestCtx(context.Background()))
+	})
+}
@@ -5,16 +5,26 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 // Errors are intentionally serialized first based on the advice in
 // https://github.com/facebook/graphql/commit/7b40390d48680b15cb93e02d46ac5eb249689876#diff-757cea6edf0288677a9eea4cfc801d87R107
 // and https://github.com/facebook/graphql/pull/384
+//
+// Label, Path and HasNext are only set on the payloads of operations using @defer or @stream.
+//
+// Fatal is set on the last response of a subscription ended by an error, the transports send its errors as an
+// error message rather than a result when their protocol has one.
 type Response struct {
 	Errors     gqlerror.List          `json:"errors,omitempty"`
 	Data       json.RawMessage        `json:"data"`
+	Label      string                 `json:"label,omitempty"`
+	Path       ast.Path               `json:"path,omitempty"`
+	HasNext    *bool                  `json:"hasNext,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	Fatal      bool                   `json:"-"`
 }
 
 func ErrorResponse(ctx context.Context, messagef string, args ...interface{}) *Response {
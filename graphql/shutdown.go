@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Shutdown coordinates the graceful shutdown of a server. Once it has started no new work is accepted, open
+// subscriptions are completed and the work in flight is waited for. handler.Server owns one, transports and
+// resolvers reach it through the request context.
+type Shutdown struct {
+	mu       sync.Mutex
+	started  bool
+	done     chan struct{}
+	aborted  chan struct{}
+	stopped  bool
+	inflight sync.WaitGroup
+}
+
+func NewShutdown() *Shutdown {
+	return &Shutdown{done: make(chan struct{}), aborted: make(chan struct{})}
+}
+
+// Done returns a channel that is closed when the shutdown starts.
+func (s *Shutdown) Done() <-chan struct{} {
+	return s.done
+}
+
+// Aborted returns a channel that is closed when Start gives up waiting for the work in flight, which should then be
+// cancelled.
+func (s *Shutdown) Aborted() <-chan struct{} {
+	return s.aborted
+}
+
+// Acquire registers work the shutdown has to wait for. It returns false once the shutdown has started, the work
+// must then be refused. Otherwise Release must be called when the work is over.
+func (s *Shutdown) Acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+// Hold registers more work on behalf of work that has been acquired and not released yet, like a response streamed
+// once the handler that acquired it has returned. Unlike Acquire it can't be refused, Release must be called when the
+// work is over.
+func (s *Shutdown) Hold() {
+	s.inflight.Add(1)
+}
+
+// Release marks work registered with Acquire or Hold as over.
+func (s *Shutdown) Release() {
+	s.inflight.Done()
+}
+
+// Start begins the shutdown and waits until all the acquired work has been released, or ctx is done in which case
+// it aborts the work in flight and returns the error of ctx. It can be called again to wait for the aborted work.
+func (s *Shutdown) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.started = true
+		close(s.done)
+	}
+	s.mu.Unlock()
+
+	released := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(released)
+	}()
+
+	select {
+	case <-released:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		if !s.stopped {
+			s.stopped = true
+			close(s.aborted)
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+const shutdownCtx key = "shutdown"
+
+// WithShutdown makes s available to the transports and resolvers serving ctx.
+func WithShutdown(ctx context.Context, s *Shutdown) context.Context {
+	if rctx, ok := ctx.(*fasthttp.RequestCtx); ok {
+		rctx.SetUserValue(string(shutdownCtx), s)
+		return rctx
+	}
+	return context.WithValue(ctx, shutdownCtx, s)
+}
+
+// GetShutdown returns the shutdown of the server serving ctx, or nil when there is none.
+func GetShutdown(ctx context.Context) *Shutdown {
+	if s, ok := ctx.Value(shutdownCtx).(*Shutdown); ok {
+		return s
+	}
+	s, _ := ctx.Value(string(shutdownCtx)).(*Shutdown)
+	return s
+}
+
+// ShuttingDown returns a channel that is closed when the server serving ctx starts shutting down. Subscription
+// resolvers can wait on it to close their channel, the transports then complete the subscription. The channel is
+// nil, and never closes, when ctx isn't served by a handler.Server.
+func ShuttingDown(ctx context.Context) <-chan struct{} {
+	if s := GetShutdown(ctx); s != nil {
+		return s.Done()
+	}
+	return nil
+}
@@ -34,9 +34,18 @@ func StartOperationTrace(ctx *fasthttp.RequestCtx) {
 	ctx.SetUserValue(string(ctxTraceStart), Now())
 }
 
+// WithOperationTrace is StartOperationTrace for the transports running operations once their request context is
+// gone, like the websocket transport.
+func WithOperationTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxTraceStart, Now())
+}
+
 // GetStartTime should only be called by the handler package, it will be set into request context
 // as Stats.Start
 func GetStartTime(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(ctxTraceStart).(time.Time); ok {
+		return t
+	}
 	t, ok := ctx.Value(string(ctxTraceStart)).(time.Time)
 	if !ok {
 		panic(fmt.Sprintf("missing start time: %T", ctx.Value(ctxTraceStart)))
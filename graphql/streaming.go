@@ -0,0 +1,18 @@
+package graphql
+
+import "context"
+
+const streamingCtx key = "streaming"
+
+// WithStreaming marks ctx as served by a transport that delivers the responses of an operation as they come, until
+// the operation ends or ctx is done, as the websocket and SSE transports do.
+func WithStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamingCtx, true)
+}
+
+// IsStreaming reports whether ctx is served by a transport that delivers the responses of an operation as they
+// come. The other transports only read the first response of queries and mutations.
+func IsStreaming(ctx context.Context) bool {
+	streaming, _ := ctx.Value(streamingCtx).(bool)
+	return streaming
+}
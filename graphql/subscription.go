@@ -0,0 +1,44 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// SubscriptionEvent is sent by the resolvers of the subscription fields configured with subscriptionEvents in
+// gqlgen.yml. An event carries either a result in Value, or an error in Err.
+type SubscriptionEvent struct {
+	Value interface{}
+
+	// Err is sent to the client along with a null result, the subscription goes on unless Fatal is set.
+	Err error
+
+	// Fatal ends the subscription with Err, no more events are read from the channel.
+	Fatal bool
+}
+
+// EventMarshaler is the result of a subscription event along with the errors it raised, which the executor adds to
+// the response of the event.
+type EventMarshaler struct {
+	Marshaler
+	Errors gqlerror.List
+	Fatal  bool
+}
+
+// NewEventMarshaler marshals m right away in ctx, a fresh response context, so the errors raised while marshaling
+// are known when the response of the event is built.
+func NewEventMarshaler(ctx context.Context, m Marshaler, fatal bool) *EventMarshaler {
+	var buf bytes.Buffer
+	m.MarshalGQL(&buf)
+
+	return &EventMarshaler{
+		Marshaler: WriterFunc(func(w io.Writer) {
+			_, _ = w.Write(buf.Bytes())
+		}),
+		Errors: GetErrors(ctx),
+		Fatal:  fatal,
+	}
+}
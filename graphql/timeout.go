@@ -0,0 +1,30 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const maxOperationDurationCtx key = "max_operation_duration"
+
+// WithMaxOperationDuration limits how long the operations of the requests served with ctx may run, the transports
+// cancel them once d has passed.
+func WithMaxOperationDuration(ctx context.Context, d time.Duration) context.Context {
+	if rctx, ok := ctx.(*fasthttp.RequestCtx); ok {
+		rctx.SetUserValue(string(maxOperationDurationCtx), d)
+		return rctx
+	}
+	return context.WithValue(ctx, maxOperationDurationCtx, d)
+}
+
+// GetMaxOperationDuration returns how long the operations of the requests served with ctx may run, or 0 when they
+// aren't limited.
+func GetMaxOperationDuration(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(maxOperationDurationCtx).(time.Duration); ok {
+		return d
+	}
+	d, _ := ctx.Value(string(maxOperationDurationCtx)).(time.Duration)
+	return d
+}